@@ -5,22 +5,31 @@ import (
 	"flag"
 	"fmt"
 	"os"
-	"path/filepath"
+	"os/signal"
+	"syscall"
 
 	"github.com/NullMeDev/Infopulse-Node/internal/config"
 	"github.com/NullMeDev/Infopulse-Node/internal/discord"
 	"github.com/NullMeDev/Infopulse-Node/internal/feeds"
 	"github.com/NullMeDev/Infopulse-Node/internal/logger"
+	"github.com/NullMeDev/Infopulse-Node/internal/sinks"
 )
 
 func main() {
 	// Parse command line flags
 	configPath := flag.String("config", "./config/config.json", "Path to configuration file")
 	logPath := flag.String("log", "", "Path to log file (overrides config)")
+	modeName := flag.String("mode", string(config.ModeFull), "What to run: full, ingest-only (no Discord) or bot-only (no feed fetching)")
 	flag.Parse()
 
+	mode, err := config.ParseMode(*modeName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+
 	// Load configuration
-	cfg, err := config.LoadConfig(*configPath)
+	cfg, err := config.LoadConfig(*configPath, mode)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
 		os.Exit(1)
@@ -40,7 +49,7 @@ func main() {
 	defer log.Close()
 
 	// Log startup
-	log.Info("Main", "Infopulse Node starting up")
+	log.Info("Main", fmt.Sprintf("Infopulse Node starting up in %s mode", mode))
 
 	// Create feed engine
 	engine, err := feeds.NewEngine(cfg, log)
@@ -49,13 +58,38 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Start feed engine
-	if err := engine.Start(); err != nil {
-		log.Critical("Main", fmt.Sprintf("Error starting feed engine: %v", err))
-		os.Exit(1)
-	}
+	// The engine also closes the store, so stop it in every mode
 	defer engine.Stop()
 
+	if mode.Ingests() {
+		// Start feed engine
+		if err := engine.Start(); err != nil {
+			log.Critical("Main", fmt.Sprintf("Error starting feed engine: %v", err))
+			os.Exit(1)
+		}
+
+		// Start outbound webhook delivery
+		if len(cfg.Sinks) > 0 {
+			dispatcher, err := sinks.NewDispatcher(cfg, engine, log)
+			if err != nil {
+				log.Critical("Main", fmt.Sprintf("Error creating sink dispatcher: %v", err))
+				os.Exit(1)
+			}
+			dispatcher.Start()
+			defer dispatcher.Stop()
+		}
+	}
+
+	if !mode.RunsBot() {
+		// Wait for interrupt signal
+		stop := make(chan os.Signal, 1)
+		signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+		<-stop
+
+		log.Info("Main", "Infopulse Node shutting down")
+		return
+	}
+
 	// Create Discord bot
 	bot, err := discord.NewBot(cfg, engine, log)
 	if err != nil {
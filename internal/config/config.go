@@ -6,12 +6,46 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/NullMeDev/Infopulse-Node/internal/models"
+	"github.com/NullMeDev/Infopulse-Node/internal/scheduler"
+	"github.com/NullMeDev/Infopulse-Node/internal/subscriptions"
 )
 
+// Mode selects which parts of the node run
+type Mode string
+
+const (
+	ModeFull       Mode = "full"        // Fetch feeds and run the Discord bot
+	ModeIngestOnly Mode = "ingest-only" // Fetch feeds and deliver to sinks, without Discord
+	ModeBotOnly    Mode = "bot-only"    // Run the Discord bot on a store filled by another instance
+)
+
+// ParseMode parses a run mode name
+func ParseMode(name string) (Mode, error) {
+	switch mode := Mode(strings.ToLower(name)); mode {
+	case ModeFull, ModeIngestOnly, ModeBotOnly:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown mode %q, expected full, ingest-only or bot-only", name)
+	}
+}
+
+// Ingests reports whether the mode fetches feeds
+func (m Mode) Ingests() bool {
+	return m != ModeBotOnly
+}
+
+// RunsBot reports whether the mode connects to Discord
+func (m Mode) RunsBot() bool {
+	return m != ModeIngestOnly
+}
+
 // Config represents application configuration
 type Config struct {
+	Mode                Mode                        `json:"-"` // Set from the command line
 	LogFilePath         string                      `json:"logFilePath"`
 	DBFilePath          string                      `json:"dbFilePath"`
 	CommandPrefix       string                      `json:"commandPrefix"`
@@ -20,18 +54,80 @@ type Config struct {
 	MaxConcurrentFetches int                        `json:"maxConcurrentFetches"`
 	AutopostEnabled     bool                        `json:"autopostEnabled"`
 	AutopostChannels    map[models.Category]string  `json:"autopostChannels"`
+	AutopostMaxAgeHours int                         `json:"autopostMaxAgeHours"`
 	FeedSources         []models.FeedSource         `json:"feedSources"`
+	WatchlistChannel    string                      `json:"watchlistChannel"`
+	WatchlistMention    string                      `json:"watchlistMention"`
+	Watchlist           []models.WatchedPackage     `json:"watchlist"`
+	WatchlistFiles      []string                    `json:"watchlistFiles"`
+	CategoryRules       []models.CategoryRule       `json:"categoryRules"`
+	CategoryMinConfidence float64                   `json:"categoryMinConfidence"`
+	PrefixCommands      bool                        `json:"prefixCommands"`
+	SlashCommands       bool                        `json:"slashCommands"`
+	SlashCommandGuildID string                      `json:"slashCommandGuildId"`
+	GuildRoles          map[string]GuildRoles       `json:"guildRoles"`     // Bot roles by guild ID
+	AuditChannelID      string                      `json:"auditChannelId"` // Channel that receives the audit log
+	Digests             []DigestConfig              `json:"digests"`        // Scheduled digest posts
+	Sinks               []SinkConfig                `json:"sinks"`          // Outbound webhooks that receive new items
+}
+
+// Sink types
+const (
+	SinkDiscord = "discord" // Discord channel webhook
+	SinkSlack   = "slack"   // Slack incoming webhook
+	SinkTeams   = "teams"   // Microsoft Teams connector
+	SinkJSON    = "json"    // Signed JSON POST to any endpoint
+)
+
+// SinkConfig sends new items matching its categories and filter to a webhook
+type SinkConfig struct {
+	ID          string            `json:"id"`          // Identifies the sink in logs and its delivery queue
+	Type        string            `json:"type"`        // discord, slack, teams or json
+	URL         string            `json:"url"`         // Webhook URL, or set it in the secrets file
+	Secret      string            `json:"secret"`      // Key for signing json payloads, or set it in the secrets file
+	Categories  []models.Category `json:"categories"`  // Categories delivered (default all)
+	Filter      string            `json:"filter"`      // Subscription filter expression, e.g. "severity:high vendor:microsoft"
+	MaxAttempts int               `json:"maxAttempts"` // Delivery attempts before an item is given up (default 5)
+}
+
+// DigestConfig schedules a digest of the top items of each category
+type DigestConfig struct {
+	Name        string            `json:"name"`        // Name used by the digest command
+	ChannelID   string            `json:"channelId"`   // Channel the digest is posted to
+	Schedule    string            `json:"schedule"`    // Cron expression, e.g. "0 8 * * *" or "0 8 * * mon"
+	Timezone    string            `json:"timezone"`    // IANA time zone of the schedule (default UTC)
+	WindowHours int               `json:"windowHours"` // Hours covered, e.g. 24 or 168 (default 24)
+	TopN        int               `json:"topN"`        // Stories per category (default 5)
+	Categories  []models.Category `json:"categories"`  // Categories to include (default all)
+}
+
+// GuildRoles lists the roles granted access to privileged commands in a
+// guild. Members with the Administrator or Manage Server permission are
+// always admins.
+type GuildRoles struct {
+	AdminRoles    []string `json:"adminRoles"`    // Role IDs with admin access
+	OperatorRoles []string `json:"operatorRoles"` // Role IDs with operator access
 }
 
 // Secrets represents sensitive configuration
 type Secrets struct {
-	BotToken string `json:"botToken"`
+	BotToken string                 `json:"botToken"`
+	Sinks    map[string]SinkSecrets `json:"sinks"` // Webhook URLs and signing keys by sink ID
+}
+
+// SinkSecrets holds the credentials of a sink. Webhook URLs embed a token,
+// so they can be kept out of the main config file.
+type SinkSecrets struct {
+	URL    string `json:"url"`
+	Secret string `json:"secret"`
 }
 
-// LoadConfig loads configuration from file
-func LoadConfig(configPath string) (*Config, error) {
+// LoadConfig loads configuration from file for a run mode. The secrets
+// file is optional in ingest-only mode, which needs no bot token.
+func LoadConfig(configPath string, mode Mode) (*Config, error) {
 	// Load main config
 	config := &Config{
+		Mode:                mode,
 		LogFilePath:         "./logs/infopulse.log",
 		DBFilePath:          "./data/intelligence.db",
 		CommandPrefix:       "!",
@@ -39,6 +135,10 @@ func LoadConfig(configPath string) (*Config, error) {
 		MaxConcurrentFetches: 5,
 		AutopostEnabled:     true,
 		AutopostChannels:    make(map[models.Category]string),
+		AutopostMaxAgeHours: 24,
+		CategoryMinConfidence: 0.5,
+		PrefixCommands:      true,
+		SlashCommands:       true,
 		FeedSources:         []models.FeedSource{},
 	}
 
@@ -59,17 +159,30 @@ func LoadConfig(configPath string) (*Config, error) {
 
 	// Read secrets file
 	secretsFile, err := os.ReadFile(secretsPath)
-	if err != nil {
+	if err != nil && !(os.IsNotExist(err) && !mode.RunsBot()) {
 		return nil, fmt.Errorf("failed to read secrets file: %v", err)
 	}
 
 	// Parse secrets
-	if err := json.Unmarshal(secretsFile, secrets); err != nil {
-		return nil, fmt.Errorf("failed to parse secrets file: %v", err)
+	if err == nil {
+		if err := json.Unmarshal(secretsFile, secrets); err != nil {
+			return nil, fmt.Errorf("failed to parse secrets file: %v", err)
+		}
 	}
 
 	// Copy secrets to config
 	config.BotToken = secrets.BotToken
+	for i := range config.Sinks {
+		sink := &config.Sinks[i]
+		if sinkSecrets, ok := secrets.Sinks[sink.ID]; ok {
+			if sinkSecrets.URL != "" {
+				sink.URL = sinkSecrets.URL
+			}
+			if sinkSecrets.Secret != "" {
+				sink.Secret = sinkSecrets.Secret
+			}
+		}
+	}
 
 	// Validate config
 	if err := validateConfig(config); err != nil {
@@ -81,7 +194,7 @@ func LoadConfig(configPath string) (*Config, error) {
 
 // validateConfig ensures configuration is valid
 func validateConfig(config *Config) error {
-	if config.BotToken == "" {
+	if config.Mode.RunsBot() && config.BotToken == "" {
 		return fmt.Errorf("bot token is required")
 	}
 
@@ -97,6 +210,38 @@ func validateConfig(config *Config) error {
 		config.MaxConcurrentFetches = 5
 	}
 
+	if config.AutopostMaxAgeHours <= 0 {
+		config.AutopostMaxAgeHours = 24
+	}
+
+	if config.CategoryMinConfidence <= 0 || config.CategoryMinConfidence > 1 {
+		config.CategoryMinConfidence = 0.5
+	}
+
+	digestNames := make(map[string]bool)
+	for i := range config.Digests {
+		digest := &config.Digests[i]
+		if err := validateDigest(digest, i); err != nil {
+			return err
+		}
+		if digestNames[digest.Name] {
+			return fmt.Errorf("duplicate digest name %q", digest.Name)
+		}
+		digestNames[digest.Name] = true
+	}
+
+	sinkIDs := make(map[string]bool)
+	for i := range config.Sinks {
+		sink := &config.Sinks[i]
+		if err := validateSink(sink); err != nil {
+			return err
+		}
+		if sinkIDs[sink.ID] {
+			return fmt.Errorf("duplicate sink id %q", sink.ID)
+		}
+		sinkIDs[sink.ID] = true
+	}
+
 	// Ensure directories exist
 	logDir := filepath.Dir(config.LogFilePath)
 	if err := os.MkdirAll(logDir, 0755); err != nil {
@@ -110,3 +255,68 @@ func validateConfig(config *Config) error {
 
 	return nil
 }
+
+// validateDigest checks a digest's schedule and fills in defaults
+func validateDigest(digest *DigestConfig, index int) error {
+	if digest.Name == "" {
+		digest.Name = fmt.Sprintf("digest-%d", index+1)
+	}
+
+	if digest.ChannelID == "" {
+		return fmt.Errorf("digest %s: channelId is required", digest.Name)
+	}
+
+	if _, err := scheduler.ParseCron(digest.Schedule); err != nil {
+		return fmt.Errorf("digest %s: %v", digest.Name, err)
+	}
+
+	if digest.Timezone == "" {
+		digest.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(digest.Timezone); err != nil {
+		return fmt.Errorf("digest %s: unknown timezone %q: %v", digest.Name, digest.Timezone, err)
+	}
+
+	if digest.WindowHours <= 0 {
+		digest.WindowHours = 24
+	}
+
+	if digest.TopN <= 0 {
+		digest.TopN = 5
+	}
+
+	if len(digest.Categories) == 0 {
+		digest.Categories = models.Categories
+	}
+
+	return nil
+}
+
+// validateSink checks a sink's type, URL and filter and fills in defaults
+func validateSink(sink *SinkConfig) error {
+	if sink.ID == "" {
+		return fmt.Errorf("sink id is required")
+	}
+
+	switch sink.Type {
+	case SinkDiscord, SinkSlack, SinkTeams, SinkJSON:
+	default:
+		return fmt.Errorf("sink %s: unknown type %q, expected discord, slack, teams or json", sink.ID, sink.Type)
+	}
+
+	if !strings.HasPrefix(sink.URL, "https://") && !strings.HasPrefix(sink.URL, "http://") {
+		return fmt.Errorf("sink %s: an http or https url is required", sink.ID)
+	}
+
+	if sink.Filter != "" {
+		if _, err := subscriptions.Parse(sink.Filter); err != nil {
+			return fmt.Errorf("sink %s: invalid filter: %v", sink.ID, err)
+		}
+	}
+
+	if sink.MaxAttempts <= 0 {
+		sink.MaxAttempts = 5
+	}
+
+	return nil
+}
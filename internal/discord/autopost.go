@@ -0,0 +1,213 @@
+// internal/discord/autopost.go
+package discord
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+	"github.com/NullMeDev/Infopulse-Node/internal/subscriptions"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// autopostInterval is how often the queue is checked without a new-items signal
+	autopostInterval = time.Minute
+	// autopostBatchSize limits how many items are posted per pass
+	autopostBatchSize = 25
+)
+
+// autopostLoop posts newly saved items to their category channels,
+// watchlist alerts to the watchlist channel, and subscription matches to
+// subscribers
+func (b *Bot) autopostLoop() {
+	defer b.wg.Done()
+
+	// Drain anything left over from a previous run
+	b.deliver()
+
+	ticker := time.NewTicker(autopostInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.engine.NewItems():
+			b.deliver()
+		case <-ticker.C:
+			b.deliver()
+		case <-b.stopChan:
+			b.logger.Info("Bot", "Autopost loop stopped")
+			return
+		}
+	}
+}
+
+// deliver runs each enabled delivery pass
+func (b *Bot) deliver() {
+	// Alerts go first since they are the most urgent
+	if b.config.WatchlistChannel != "" {
+		b.postWatchAlerts()
+	}
+	if b.config.AutopostEnabled {
+		b.postPending()
+	}
+	b.postSubscriptions()
+}
+
+// postWatchAlerts posts queued watchlist alerts until the queue is empty or a send fails
+func (b *Bot) postWatchAlerts() {
+	channelID := b.config.WatchlistChannel
+
+	for {
+		alerts := b.engine.GetPendingWatchAlerts(autopostBatchSize)
+		if len(alerts) == 0 {
+			return
+		}
+
+		for _, alert := range alerts {
+			select {
+			case <-b.stopChan:
+				return
+			default:
+			}
+
+			content := "Advisory affects watched dependencies"
+			if b.config.WatchlistMention != "" {
+				content = b.config.WatchlistMention + " " + content
+			}
+
+			_, err := b.session.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+				Content: content,
+				Embed:   createWatchAlertEmbed(alert),
+			})
+			if err != nil {
+				// Leave the alert queued and retry on the next pass
+				b.logger.Error("Bot", fmt.Sprintf("Failed to post watchlist alert %s: %v", alert.Item.ID, err))
+				return
+			}
+
+			if err := b.engine.MarkWatchAlertPosted(alert.Item.ID, channelID); err != nil {
+				b.logger.Error("Bot", fmt.Sprintf("Failed to mark alert %s as posted: %v", alert.Item.ID, err))
+				return
+			}
+		}
+
+		if len(alerts) < autopostBatchSize {
+			return
+		}
+	}
+}
+
+// postPending posts queued items until the queue is empty or a send fails
+func (b *Bot) postPending() {
+	maxAge := time.Duration(b.config.AutopostMaxAgeHours) * time.Hour
+	if expired := b.engine.ExpireAutoposts(maxAge); expired > 0 {
+		b.logger.Info("Bot", fmt.Sprintf("Skipped %d queued items older than %v", expired, maxAge))
+	}
+
+	for {
+		items := b.engine.GetPendingAutoposts(autopostBatchSize)
+		if len(items) == 0 {
+			return
+		}
+
+		for _, item := range items {
+			select {
+			case <-b.stopChan:
+				return
+			default:
+			}
+
+			channelID := b.config.AutopostChannels[item.Category]
+			if channelID != "" {
+				if _, err := b.session.ChannelMessageSendEmbed(channelID, createItemEmbed(item)); err != nil {
+					// Leave the item queued and retry on the next pass
+					b.logger.Error("Bot", fmt.Sprintf("Failed to autopost %s to %s: %v", item.ID, channelID, err))
+					return
+				}
+			}
+
+			// Items without a configured channel are marked handled so they don't block the queue
+			if err := b.engine.MarkPosted(item.ID, channelID); err != nil {
+				b.logger.Error("Bot", fmt.Sprintf("Failed to mark %s as posted: %v", item.ID, err))
+				return
+			}
+		}
+
+		if len(items) < autopostBatchSize {
+			return
+		}
+	}
+}
+
+// postSubscriptions delivers queued subscription matches. A failed delivery
+// is retried on a later pass, and given up after several attempts so that
+// users who don't accept DMs don't hold up the queue.
+func (b *Bot) postSubscriptions() {
+	dmChannels := make(map[string]string)
+
+	for {
+		deliveries := b.engine.GetPendingDeliveries(autopostBatchSize)
+		if len(deliveries) == 0 {
+			return
+		}
+
+		failed := 0
+		for _, delivery := range deliveries {
+			select {
+			case <-b.stopChan:
+				return
+			default:
+			}
+
+			sub, item := delivery.Subscription, delivery.Item
+			if err := b.sendSubscriptionItem(sub, item, dmChannels); err != nil {
+				failed++
+				givenUp, markErr := b.engine.MarkDeliveryFailed(sub.ID, item.ID)
+				if markErr != nil {
+					b.logger.Error("Bot", fmt.Sprintf("Failed to record delivery failure: %v", markErr))
+					return
+				}
+				if givenUp {
+					b.logger.Warning("Bot", fmt.Sprintf("Gave up delivering %s to subscription %d: %v", item.ID, sub.ID, err))
+				} else {
+					b.logger.Error("Bot", fmt.Sprintf("Failed to deliver %s to subscription %d: %v", item.ID, sub.ID, err))
+				}
+				continue
+			}
+
+			if err := b.engine.MarkDelivered(sub.ID, item.ID); err != nil {
+				b.logger.Error("Bot", fmt.Sprintf("Failed to mark %s as delivered: %v", item.ID, err))
+				return
+			}
+		}
+
+		// Leave failed deliveries for the next pass
+		if failed > 0 || len(deliveries) < autopostBatchSize {
+			return
+		}
+	}
+}
+
+// sendSubscriptionItem posts an item to a subscription's channel, or by DM
+// to its user
+func (b *Bot) sendSubscriptionItem(sub *models.Subscription, item *models.Intelligence, dmChannels map[string]string) error {
+	channelID := sub.ChannelID
+	if channelID == "" {
+		channelID = dmChannels[sub.UserID]
+		if channelID == "" {
+			channel, err := b.session.UserChannelCreate(sub.UserID)
+			if err != nil {
+				return fmt.Errorf("failed to open DM channel: %v", err)
+			}
+			channelID = channel.ID
+			dmChannels[sub.UserID] = channelID
+		}
+	}
+
+	_, err := b.session.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Content: truncate(fmt.Sprintf("Subscription #%d: `%s`", sub.ID, subscriptions.Format(sub.Filter)), 2000),
+		Embed:   createItemEmbed(item),
+	})
+	return err
+}
@@ -5,7 +5,11 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/NullMeDev/Infopulse-Node/internal/config"
 	"github.com/NullMeDev/Infopulse-Node/internal/feeds"
@@ -20,11 +24,13 @@ type Bot struct {
 	config   *config.Config
 	engine   *feeds.Engine
 	logger   *logger.Logger
-	commands map[string]CommandHandler
-}
+	commands *Registry
+	stopChan chan struct{}
+	wg       sync.WaitGroup
 
-// CommandHandler is a function that handles a command
-type CommandHandler func(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error
+	pagersMu sync.Mutex
+	pagers   map[string]*pager
+}
 
 // NewBot creates a new Discord bot
 func NewBot(cfg *config.Config, engine *feeds.Engine, logger *logger.Logger) (*Bot, error) {
@@ -40,11 +46,21 @@ func NewBot(cfg *config.Config, engine *feeds.Engine, logger *logger.Logger) (*B
 		config:   cfg,
 		engine:   engine,
 		logger:   logger,
-		commands: make(map[string]CommandHandler),
+		commands: NewRegistry(),
+		stopChan: make(chan struct{}),
+		pagers:   make(map[string]*pager),
 	}
 
-	// Register message handler
-	session.AddHandler(bot.messageHandler)
+	// Register message handler. Reading prefix commands in guilds needs the
+	// privileged message content intent.
+	if cfg.PrefixCommands {
+		session.Identify.Intents |= discordgo.IntentsMessageContent
+		session.AddHandler(bot.messageHandler)
+	}
+
+	// Register interaction handler. Buttons on command replies need it
+	// even without slash commands.
+	session.AddHandler(bot.interactionHandler)
 
 	// Register commands
 	bot.registerCommands()
@@ -60,12 +76,36 @@ func (b *Bot) Start() error {
 	}
 
 	b.logger.Info("Bot", "Discord bot started")
+
+	// Register slash commands. Prefix commands keep working if this fails.
+	if b.config.SlashCommands {
+		if err := b.registerSlashCommands(); err != nil {
+			b.logger.Error("Bot", fmt.Sprintf("Failed to register slash commands: %v", err))
+		}
+	}
+
+	// Start autoposter. It also delivers subscriptions, which can be
+	// added at any time.
+	b.wg.Add(1)
+	go b.autopostLoop()
+
+	// Start digest scheduler
+	if len(b.config.Digests) > 0 {
+		b.wg.Add(1)
+		go b.digestLoop()
+	}
+
 	return nil
 }
 
 // Stop stops the Discord bot
 func (b *Bot) Stop() error {
 	b.logger.Info("Bot", "Stopping Discord bot")
+
+	// Stop background loops before closing the session
+	close(b.stopChan)
+	b.wg.Wait()
+
 	return b.session.Close()
 }
 
@@ -100,149 +140,330 @@ func (b *Bot) messageHandler(s *discordgo.Session, m *discordgo.MessageCreate) {
 
 // handleCommand processes a command message
 func (b *Bot) handleCommand(s *discordgo.Session, m *discordgo.MessageCreate) {
-	// Parse command and arguments
-	command, args := parseCommand(m.Content[len(b.config.CommandPrefix):])
+	ctx := newMessageContext(s, m)
 
-	// Log command
-	b.logger.Info("Bot", fmt.Sprintf("Command received: %s %v from %s", 
-		command, args, m.Author.Username))
+	// Parse command and arguments
+	command, tokens, err := parseCommand(m.Content[len(b.config.CommandPrefix):])
+	if err != nil {
+		ctx.ReplyError(err)
+		return
+	}
 
-	// Look up command handler
-	handler, exists := b.commands[command]
+	// Look up command
+	cmd, exists := b.commands.Lookup(command)
 	if !exists {
 		// Unknown command
-		s.ChannelMessageSend(m.ChannelID, 
-			fmt.Sprintf("Unknown command: %s. Type %shelp for available commands.", 
-				command, b.config.CommandPrefix))
+		ctx.Replyf("Unknown command: %s. Type %shelp for available commands.", 
+			command, b.config.CommandPrefix)
+		return
+	}
+
+	args, err := b.parseArgs(cmd, tokens)
+	if err != nil {
+		ctx.ReplyError(err)
 		return
 	}
 
+	b.runCommand(ctx, cmd, args)
+}
+
+// runCommand checks permissions and executes a command
+func (b *Bot) runCommand(ctx *Context, cmd *Command, args Args) {
+	// Log command
+	b.logger.Info("Bot", fmt.Sprintf("Command received: %s %v from %s", 
+		cmd.Name, args, ctx.Username()))
+
+	// Check and audit privileged commands
+	if cmd.Permission > PermissionEveryone {
+		allowed := b.permissionLevel(ctx) >= cmd.Permission
+		b.audit(ctx, cmd, args, allowed)
+		if !allowed {
+			ctx.ReplyError(fmt.Errorf("you need %s access to use this command", cmd.Permission))
+			return
+		}
+	}
+
 	// Execute command
-	if err := handler(s, m, args); err != nil {
+	if err := cmd.Handler(ctx, args); err != nil {
 		// Command error
-		s.ChannelMessageSend(m.ChannelID, 
-			fmt.Sprintf("Error executing command: %v", err))
+		ctx.ReplyError(err)
 		b.logger.Error("Bot", fmt.Sprintf("Command error: %v", err))
 	}
 }
 
-// parseCommand splits a message into command and arguments
-func parseCommand(content string) (string, []string) {
-	// TODO: Implement proper command parsing with quoted arguments
-	// For now, just split by space
-	// Command is the first word, args are the rest
-	return "help", []string{} // Placeholder
-}
-
 // registerCommands registers all command handlers
 func (b *Bot) registerCommands() {
 	// Register help command
-	b.commands["help"] = b.helpCommand
+	b.commands.Add(&Command{
+		Name:        "help",
+		Description: "Show available commands, or details of one command",
+		Args: []*Arg{
+			{Name: "command", Type: ArgString, Description: "Command to describe"},
+		},
+		Ephemeral: true,
+		Handler:   b.helpCommand,
+	})
 	
 	// Register intelligence commands
-	b.commands["latest"] = b.latestCommand
-	b.commands["intel"] = b.intelCommand
-	b.commands["cybersec"] = b.categoryCommand(models.CategoryCybersec)
-	b.commands["aitools"] = b.categoryCommand(models.CategoryAITools)
-	b.commands["opensource"] = b.categoryCommand(models.CategoryOpenSource)
-	b.commands["infosec"] = b.categoryCommand(models.CategoryInfosecNews)
+	b.commands.Add(&Command{
+		Name:        "latest",
+		Description: "Show latest intelligence items",
+		Args:        listArgs(true),
+		Handler:     b.latestCommand,
+	})
+	b.commands.Add(&Command{
+		Name:        "intel",
+		Description: "Show details for a specific intelligence item",
+		Args: []*Arg{
+			{Name: "id", Type: ArgString, Description: "Item ID, or its first few characters", Required: true},
+		},
+		Handler: b.intelCommand,
+	})
+	b.commands.Add(&Command{
+		Name:        "bookmarks",
+		Description: "Show the items you have bookmarked",
+		Args: []*Arg{
+			{
+				Name:        "count",
+				Type:        ArgInt,
+				Description: "Number of items per page",
+				Default:     strconv.Itoa(defaultListCount),
+				Min:         1,
+				Max:         maxListCount,
+			},
+		},
+		Ephemeral: true,
+		Handler:   b.bookmarksCommand,
+	})
+	b.commands.Add(&Command{
+		Name:        "search",
+		Description: "Search stored intelligence",
+		Args:        searchArgs(),
+		Handler:     b.searchCommand,
+	})
+	b.commands.Add(&Command{
+		Name:        "subscribe",
+		Description: "Get new items matching a filter by DM, or in a channel",
+		Args: []*Arg{
+			{
+				Name:        "filter",
+				Type:        ArgText,
+				Required:    true,
+				Description: "Terms such as category:cybersec source:nvd severity:high vendor:microsoft and keywords",
+			},
+			{
+				Name:        "channel",
+				Type:        ArgString,
+				Flag:        true,
+				Description: `Channel to post matches to instead of a DM, or "here"`,
+			},
+		},
+		Ephemeral: true,
+		Handler:   b.subscribeCommand,
+	})
+	b.commands.Add(&Command{
+		Name:        "unsubscribe",
+		Description: "Remove a subscription",
+		Args: []*Arg{
+			{Name: "id", Type: ArgInt, Description: "Subscription number", Required: true},
+		},
+		Ephemeral: true,
+		Handler:   b.unsubscribeCommand,
+	})
+	b.commands.Add(&Command{
+		Name:        "subscriptions",
+		Description: "Show your subscriptions and this server's channel subscriptions",
+		Ephemeral:   true,
+		Handler:     b.subscriptionsCommand,
+	})
+	b.commands.Add(&Command{
+		Name:        "digest",
+		Description: "Preview a digest of the top stories per category, or post it now",
+		Args: []*Arg{
+			{Name: "action", Type: ArgString, Description: "preview, or post to the digest's channel", Default: "preview"},
+			{Name: "name", Type: ArgString, Description: "Configured digest, e.g. daily or weekly"},
+		},
+		Ephemeral: true,
+		Handler:   b.digestCommand,
+	})
+	b.commands.Add(b.categoryCommand("cybersec", models.CategoryCybersec, "Show latest cybersecurity intelligence"))
+	b.commands.Add(b.categoryCommand("aitools", models.CategoryAITools, "Show latest AI tools intelligence"))
+	b.commands.Add(b.categoryCommand("opensource", models.CategoryOpenSource, "Show latest open source intelligence"))
+	b.commands.Add(b.categoryCommand("infosec", models.CategoryInfosecNews, "Show latest infosec news"))
 	
 	// Register admin commands
-	b.commands["status"] = b.statusCommand
-	b.commands["refresh"] = b.refreshCommand
+	b.commands.Add(&Command{
+		Name:        "status",
+		Description: "Show bot status",
+		Ephemeral:   true,
+		Handler:     b.statusCommand,
+	})
+	b.commands.Add(&Command{
+		Name:        "schedule",
+		Description: "Show when each feed source is next fetched",
+		Ephemeral:   true,
+		Handler:     b.scheduleCommand,
+	})
+	b.commands.Add(&Command{
+		Name:        "watch",
+		Description: "Alert on advisories for a package, or for the packages in an attached go.sum or package-lock.json",
+		Args: []*Arg{
+			{Name: "ecosystem", Type: ArgString, Description: "Package ecosystem, e.g. Go, npm or PyPI"},
+			{Name: "package", Type: ArgString, Description: "Package name"},
+			{Name: "version", Type: ArgString, Description: "Version in use"},
+			{Name: "file", Type: ArgAttachment, Description: "go.sum or package-lock.json to import"},
+		},
+		Permission: PermissionAdmin,
+		Ephemeral:  true,
+		Handler:    b.watchCommand,
+	})
+	b.commands.Add(&Command{
+		Name:        "unwatch",
+		Description: "Stop alerting on a package",
+		Args: []*Arg{
+			{Name: "ecosystem", Type: ArgString, Description: "Package ecosystem", Required: true},
+			{Name: "package", Type: ArgString, Description: "Package name", Required: true},
+		},
+		Permission: PermissionAdmin,
+		Ephemeral:  true,
+		Handler:    b.unwatchCommand,
+	})
+	b.commands.Add(&Command{
+		Name:        "watchlist",
+		Description: "Show watched packages",
+		Ephemeral:   true,
+		Handler:     b.watchlistCommand,
+	})
+	b.commands.Add(&Command{
+		Name:        "refresh",
+		Description: "Force refresh of intelligence feeds",
+		Permission:  PermissionOperator,
+		Ephemeral:   true,
+		Handler:     b.refreshCommand,
+	})
 }
 
 // Command handlers
 
 // helpCommand handles the help command
-func (b *Bot) helpCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+func (b *Bot) helpCommand(ctx *Context, args Args) error {
+	if name := args.String("command"); name != "" {
+		cmd, exists := b.commands.Lookup(strings.TrimPrefix(name, b.config.CommandPrefix))
+		if !exists {
+			return fmt.Errorf("unknown command: %s", name)
+		}
+		return ctx.ReplyEmbed(b.commandHelpEmbed(cmd))
+	}
+
 	embed := &discordgo.MessageEmbed{
 		Title:       "Infopulse Node Help",
-		Description: "Available commands:",
+		Description: fmt.Sprintf("Available commands. Type %shelp <command> for details.", b.config.CommandPrefix),
 		Color:       0x00ff00,
-		Fields: []*discordgo.MessageEmbedField{
-			{
-				Name:  b.config.CommandPrefix + "help",
-				Value: "Show this help message",
-			},
-			{
-				Name:  b.config.CommandPrefix + "latest [count]",
-				Value: "Show latest intelligence items",
-			},
-			{
-				Name:  b.config.CommandPrefix + "intel <id>",
-				Value: "Show details for a specific intelligence item",
-			},
-			{
-				Name:  b.config.CommandPrefix + "cybersec [count]",
-				Value: "Show latest cybersecurity intelligence",
-			},
-			{
-				Name:  b.config.CommandPrefix + "aitools [count]",
-				Value: "Show latest AI tools intelligence",
-			},
-			{
-				Name:  b.config.CommandPrefix + "opensource [count]",
-				Value: "Show latest open source intelligence",
-			},
-			{
-				Name:  b.config.CommandPrefix + "infosec [count]",
-				Value: "Show latest infosec news",
-			},
-			{
-				Name:  b.config.CommandPrefix + "status",
-				Value: "Show bot status",
-			},
-			{
-				Name:  b.config.CommandPrefix + "refresh",
-				Value: "Force refresh of intelligence feeds (admin only)",
-			},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Infopulse Node v1.0",
 		},
+	}
+
+	for _, cmd := range b.commands.Commands() {
+		if len(embed.Fields) >= maxEmbedFields {
+			break
+		}
+
+		value := cmd.Description
+		if cmd.Permission > PermissionEveryone {
+			value += fmt.Sprintf(" (%s only)", cmd.Permission)
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  truncate(b.config.CommandPrefix+cmd.Usage(), maxFieldName),
+			Value: truncate(value, maxFieldValue),
+		})
+	}
+
+	return ctx.ReplyEmbed(embed)
+}
+
+// commandHelpEmbed describes a single command and its arguments
+func (b *Bot) commandHelpEmbed(cmd *Command) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:       truncate(b.config.CommandPrefix+cmd.Usage(), maxEmbedTitle),
+		Description: cmd.Description,
+		Color:       0x00ff00,
 		Footer: &discordgo.MessageEmbedFooter{
 			Text: "Infopulse Node v1.0",
 		},
 	}
 
-	_, err := s.ChannelMessageSendEmbed(m.ChannelID, embed)
-	return err
+	if len(cmd.Args) > 0 {
+		var lines []string
+		for _, arg := range cmd.Args {
+			line := fmt.Sprintf("`%s` %s", arg.Name, arg.Description)
+			if len(arg.Aliases) > 0 {
+				line += fmt.Sprintf(" (also `%s`)", strings.Join(arg.Aliases, "`, `"))
+			}
+			if arg.Default != "" {
+				line += fmt.Sprintf(", default %s", arg.Default)
+			}
+			lines = append(lines, line)
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "Arguments",
+			Value: truncate(strings.Join(lines, "\n"), maxFieldValue),
+		})
+	}
+
+	if len(cmd.Aliases) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "Aliases",
+			Value: strings.Join(cmd.Aliases, ", "),
+		})
+	}
+
+	if cmd.Permission > PermissionEveryone {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "Permission",
+			Value: fmt.Sprintf("%s only", cmd.Permission),
+		})
+	}
+
+	return embed
 }
 
 // latestCommand handles the latest command
-func (b *Bot) latestCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
-	// Get latest intel
-	items := b.engine.GetLatestIntel("", 10) // Default limit to 10
-	
-	// Create embed
-	embed := createIntelEmbed("Latest Intelligence", items)
-	
-	// Send embed
-	_, err := s.ChannelMessageSendEmbed(m.ChannelID, embed)
-	return err
-}
+func (b *Bot) latestCommand(ctx *Context, args Args) error {
+	filter, size := listFilter(ctx, args)
 
-// intelCommand handles the intel command
-func (b *Bot) intelCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
-	// TODO: Implement
-	return nil
+	title := "Latest Intelligence"
+	if filter.ExploitedOnly {
+		title = "Latest Actively Exploited"
+	}
+
+	return b.replyPages(ctx, title, size, b.listPages(filter))
 }
 
-// categoryCommand creates a command handler for a specific category
-func (b *Bot) categoryCommand(category models.Category) CommandHandler {
-	return func(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
-		// Get intel for category
-		items := b.engine.GetLatestIntel(category, 10) // Default limit to 10
-		
-		// Create embed
-		embed := createIntelEmbed(fmt.Sprintf("%s Intelligence", category), items)
-		
-		// Send embed
-		_, err := s.ChannelMessageSendEmbed(m.ChannelID, embed)
-		return err
+// categoryCommand creates a command for a specific category
+func (b *Bot) categoryCommand(name string, category models.Category, description string) *Command {
+	handler := func(ctx *Context, args Args) error {
+		filter, size := listFilter(ctx, args)
+		filter.Category = category
+
+		title := fmt.Sprintf("%s Intelligence", category)
+		if filter.ExploitedOnly {
+			title = fmt.Sprintf("%s Actively Exploited", category)
+		}
+
+		return b.replyPages(ctx, title, size, b.listPages(filter))
+	}
+
+	return &Command{
+		Name:        name,
+		Description: description,
+		Args:        listArgs(false),
+		Handler:     handler,
 	}
 }
 
 // statusCommand handles the status command
-func (b *Bot) statusCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+func (b *Bot) statusCommand(ctx *Context, args Args) error {
 	// Get stats
 	totalItems := b.engine.GetTotalCount()
 	
@@ -269,35 +490,141 @@ func (b *Bot) statusCommand(s *discordgo.Session, m *discordgo.MessageCreate, ar
 		},
 	}
 	
+	// Add processing pipeline metrics
+	var stages []string
+	for _, stage := range b.engine.GetPipelineMetrics() {
+		line := fmt.Sprintf("`%s` %d in, %d out, %d errors", stage.Name, stage.ItemsIn, stage.ItemsOut, stage.Errors)
+		if stage.LastError != "" {
+			line += fmt.Sprintf(" (last: %s)", truncate(stage.LastError, 100))
+		}
+		stages = append(stages, line)
+	}
+	if len(stages) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "Processing Pipeline",
+			Value: truncate(strings.Join(stages, "\n"), maxFieldValue),
+		})
+	}
+	
 	// Send embed
-	_, err := s.ChannelMessageSendEmbed(m.ChannelID, embed)
-	return err
+	return ctx.ReplyEmbed(embed)
+}
+
+// scheduleCommand handles the schedule command
+func (b *Bot) scheduleCommand(ctx *Context, args Args) error {
+	if !b.config.Mode.Ingests() {
+		return ctx.Reply("This instance runs in bot-only mode; feeds are fetched on the schedule of a separate ingest instance.")
+	}
+
+	entries := b.engine.GetSchedule()
+	if len(entries) == 0 {
+		return ctx.Reply("No feed sources are scheduled.")
+	}
+
+	// Build one line per source
+	var lines []string
+	now := time.Now()
+	states := b.engine.GetFetchStates()
+	for _, entry := range entries {
+		next := "running"
+		if !entry.Running {
+			next = "in " + entry.NextRun.Sub(now).Round(time.Second).String()
+			if !entry.NextRun.After(now) {
+				next = "due"
+			}
+		}
+
+		last := "never"
+		if !entry.LastRun.IsZero() {
+			last = now.Sub(entry.LastRun).Round(time.Second).String() + " ago"
+		}
+
+		line := fmt.Sprintf("`%s` every %v, next %s, last %s",
+			entry.ID, entry.Interval, next, last)
+		if state, ok := states[entry.ID]; ok && (!state.LastFetched.IsZero() || state.LastError != "") {
+			if state.LastError != "" {
+				line += fmt.Sprintf(" (error: %s)", state.LastError)
+			} else if state.LastStatus == 0 {
+				// Local sources have no HTTP status
+				line += fmt.Sprintf(" (%d bytes)", state.LastBytes)
+			} else {
+				line += fmt.Sprintf(" (HTTP %d, %d bytes)", state.LastStatus, state.LastBytes)
+			}
+		}
+		lines = append(lines, line)
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "Feed Schedule",
+		Description: truncate(strings.Join(lines, "\n"), maxEmbedDescription),
+		Color:       0x0000ff,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Infopulse Node v1.0",
+		},
+	}
+
+	return ctx.ReplyEmbed(embed)
 }
 
 // refreshCommand handles the refresh command
-func (b *Bot) refreshCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
-	// Check if user has admin role
-	if !b.isAdmin(m.Member) {
-		return fmt.Errorf("you do not have permission to use this command")
+func (b *Bot) refreshCommand(ctx *Context, args Args) error {
+	if !b.config.Mode.Ingests() {
+		return ctx.Reply("Refresh is unavailable in bot-only mode; feeds are fetched by a separate ingest instance.")
 	}
-	
-	// TODO: Trigger a manual refresh of feeds
-	
+
+	// Make every source due now
+	b.engine.RefreshFeeds()
+
 	// Send response
-	_, err := s.ChannelMessageSend(m.ChannelID, "Refreshing intelligence feeds...")
-	return err
+	return ctx.Reply("Refreshing intelligence feeds...")
 }
 
-// isAdmin checks if a user has an admin role
-func (b *Bot) isAdmin(member *discordgo.Member) bool {
-	if member == nil {
-		return false
+// permissionLevel returns the bot permission level of the invoking user.
+// Outside guilds everyone has the lowest level.
+func (b *Bot) permissionLevel(ctx *Context) Permission {
+	if ctx.GuildID == "" || ctx.Member == nil {
+		return PermissionEveryone
 	}
-	
-	for _, roleID := range member.Roles {
-		// TODO: Check if role is in admin roles list
-		// For now, just return true
+
+	// Server managers administer the bot in their server
+	if checkPermission(ctx, discordgo.PermissionManageServer) {
+		return PermissionAdmin
+	}
+
+	roles := b.config.GuildRoles[ctx.GuildID]
+	switch {
+	case hasAnyRole(ctx.Member.Roles, roles.AdminRoles):
+		return PermissionAdmin
+	case hasAnyRole(ctx.Member.Roles, roles.OperatorRoles):
+		return PermissionOperator
+	default:
+		return PermissionEveryone
+	}
+}
+
+// audit records a privileged command in the log and the audit channel
+func (b *Bot) audit(ctx *Context, cmd *Command, args Args, allowed bool) {
+	userID := ""
+	if ctx.User != nil {
+		userID = ctx.User.ID
+	}
+
+	outcome := "ran"
+	if !allowed {
+		outcome = "was denied"
+	}
+	message := fmt.Sprintf("%s (%s) %s %s command %s %v in guild %s channel %s",
+		ctx.Username(), userID, outcome, cmd.Permission, cmd.Name, map[string]interface{}(args), ctx.GuildID, ctx.ChannelID)
+
+	if allowed {
+		b.logger.Info("Audit", message)
+	} else {
+		b.logger.Warning("Audit", message)
+	}
+
+	if b.config.AuditChannelID != "" {
+		if _, err := b.session.ChannelMessageSend(b.config.AuditChannelID, truncate(message, 2000)); err != nil {
+			b.logger.Error("Bot", fmt.Sprintf("Failed to post to audit channel: %v", err))
+		}
 	}
-	
-	return true
 }
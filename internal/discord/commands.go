@@ -4,61 +4,156 @@ package discord
 import (
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/feeds"
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+	"github.com/bwmarrin/discordgo"
 )
 
-// parseCommand splits a message into command and arguments
-func parseCommand(content string) (string, []string) {
-	// Trim leading/trailing whitespace
-	content = strings.TrimSpace(content)
-	
-	// Split into words
-	words := strings.Fields(content)
+const (
+	// defaultListCount is the number of items shown by list commands
+	defaultListCount = 10
+	// maxListCount is the most items a single list embed can show
+	maxListCount = maxEmbedFields
+)
+
+// parseCommand splits a message into command and arguments, keeping
+// quoted arguments together
+func parseCommand(content string) (string, []string, error) {
+	words, err := tokenize(content)
+	if err != nil {
+		return "", nil, err
+	}
 	
 	// If no words, return empty command and args
 	if len(words) == 0 {
-		return "", []string{}
+		return "", []string{}, nil
 	}
 	
-	// First word is the command
-	command := strings.ToLower(words[0])
-	
-	// Rest are args
-	var args []string
-	if len(words) > 1 {
-		args = words[1:]
+	// First word is the command, the rest are args
+	return strings.ToLower(words[0]), words[1:], nil
+}
+
+// listArgs returns the arguments of list commands
+func listArgs(withCategory bool) []*Arg {
+	args := []*Arg{
+		{
+			Name:        "count",
+			Type:        ArgInt,
+			Description: "Number of items per page",
+			Default:     strconv.Itoa(defaultListCount),
+			Min:         1,
+			Max:         maxListCount,
+		},
+		{
+			Name:        "exploited",
+			Type:        ArgBool,
+			Aliases:     []string{"kev"},
+			Description: "Only show actively exploited vulnerabilities",
+		},
+		{
+			Name:        "unread",
+			Type:        ArgBool,
+			Description: "Hide items you have marked as read",
+		},
+		{
+			Name:        "source",
+			Type:        ArgSource,
+			Flag:        true,
+			Description: "Only show items from this source",
+		},
+		{
+			Name:        "since",
+			Type:        ArgDuration,
+			Flag:        true,
+			Description: "Only show items published within this time, e.g. 24h or 7d",
+		},
 	}
-	
-	return command, args
+
+	if withCategory {
+		args = append(args, &Arg{
+			Name:        "category",
+			Type:        ArgCategory,
+			Flag:        true,
+			Description: "Only show items in this category",
+		})
+	}
+
+	return args
 }
 
-// getIntArg parses an integer argument with a default value
-func getIntArg(args []string, index int, defaultVal int) int {
-	if len(args) <= index {
-		return defaultVal
+// listFilter builds the query of a list command from its arguments
+func listFilter(ctx *Context, args Args) (feeds.IntelFilter, int) {
+	filter := feeds.IntelFilter{
+		Category:      args.Category("category"),
+		SourceID:      args.String("source"),
+		ExploitedOnly: args.Bool("exploited"),
 	}
-	
-	val, err := strconv.Atoi(args[index])
-	if err != nil {
-		return defaultVal
+	if args.Bool("unread") && ctx.User != nil {
+		filter.UnreadBy = ctx.User.ID
 	}
-	
-	return val
+	if since := args.Duration("since"); since > 0 {
+		filter.Since = time.Now().UTC().Add(-since)
+	}
+
+	return filter, args.Int("count")
 }
 
-// getStringArg gets a string argument with a default value
-func getStringArg(args []string, index int, defaultVal string) string {
-	if len(args) <= index {
-		return defaultVal
+// listPages pages through the latest items matching a filter
+func (b *Bot) listPages(filter feeds.IntelFilter) pageFetcher {
+	return func(start pageStart, limit int) ([]*models.Intelligence, error) {
+		page := filter
+		page.After = start.after
+		return b.engine.QueryIntel(page, limit), nil
 	}
-	
-	return args[index]
 }
 
-// checkPermission checks if a user has a permission in a channel
-func checkPermission(userID, channelID, guildID string, permission int64, s *interface{}) bool {
-	// TODO: Implement permission check
-	// This is a placeholder that always returns true
-	return true
+// parseCategory matches a category name case-insensitively
+func parseCategory(value string) (models.Category, bool) {
+	for _, category := range models.Categories {
+		if strings.EqualFold(value, string(category)) {
+			return category, true
+		}
+	}
+	return "", false
+}
+
+// findSource looks up a configured feed source by ID
+func (b *Bot) findSource(id string) (models.FeedSource, bool) {
+	for _, source := range b.config.FeedSources {
+		if strings.EqualFold(source.ID, id) {
+			return source, true
+		}
+	}
+	return models.FeedSource{}, false
+}
+
+// checkPermission checks if the invoking member has a Discord permission
+// in the channel. Guild owners and administrators have every permission.
+func checkPermission(ctx *Context, permission int64) bool {
+	if ctx.GuildID == "" || ctx.User == nil {
+		return false
+	}
+
+	// Interactions carry the member's computed channel permissions
+	permissions := int64(0)
+	if ctx.Member != nil {
+		permissions = ctx.Member.Permissions
+	}
+
+	if permissions == 0 {
+		var err error
+		permissions, err = ctx.Session.State.UserChannelPermissions(ctx.User.ID, ctx.ChannelID)
+		if err != nil {
+			permissions, err = ctx.Session.UserChannelPermissions(ctx.User.ID, ctx.ChannelID)
+			if err != nil {
+				return false
+			}
+		}
+	}
+
+	return permissions&discordgo.PermissionAdministrator != 0 || permissions&permission == permission
 }
 
 // hasRole checks if a user has a specific role
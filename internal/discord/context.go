@@ -0,0 +1,147 @@
+// internal/discord/context.go
+package discord
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Context is one invocation of a command, from either a prefixed message
+// or a slash command. Handlers reply through it so they work for both.
+type Context struct {
+	Session     *discordgo.Session
+	GuildID     string
+	ChannelID   string
+	User        *discordgo.User
+	Member      *discordgo.Member
+	Attachments []*discordgo.MessageAttachment
+
+	// Interaction is set for slash commands
+	Interaction *discordgo.Interaction
+	// Ephemeral makes slash command replies visible only to the invoker
+	Ephemeral bool
+
+	responded bool
+}
+
+// newMessageContext creates a context for a prefixed message
+func newMessageContext(s *discordgo.Session, m *discordgo.MessageCreate) *Context {
+	return &Context{
+		Session:     s,
+		GuildID:     m.GuildID,
+		ChannelID:   m.ChannelID,
+		User:        m.Author,
+		Member:      m.Member,
+		Attachments: m.Attachments,
+	}
+}
+
+// newInteractionContext creates a context for a slash command
+func newInteractionContext(s *discordgo.Session, i *discordgo.InteractionCreate, ephemeral bool) *Context {
+	ctx := &Context{
+		Session:     s,
+		GuildID:     i.GuildID,
+		ChannelID:   i.ChannelID,
+		User:        i.User,
+		Member:      i.Member,
+		Interaction: i.Interaction,
+		Ephemeral:   ephemeral,
+	}
+
+	// Guild interactions carry the user in the member
+	if ctx.User == nil && i.Member != nil {
+		ctx.User = i.Member.User
+	}
+
+	return ctx
+}
+
+// Username returns the name of the invoking user
+func (c *Context) Username() string {
+	if c.User == nil {
+		return "unknown"
+	}
+	return c.User.Username
+}
+
+// Reply sends a text reply
+func (c *Context) Reply(content string) error {
+	return c.send(&discordgo.MessageSend{Content: content}, c.Ephemeral)
+}
+
+// Replyf sends a formatted text reply
+func (c *Context) Replyf(format string, args ...interface{}) error {
+	return c.Reply(fmt.Sprintf(format, args...))
+}
+
+// ReplyEmbed sends an embed reply
+func (c *Context) ReplyEmbed(embed *discordgo.MessageEmbed) error {
+	return c.send(&discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{embed}}, c.Ephemeral)
+}
+
+// ReplyMessage sends a reply with embeds and components
+func (c *Context) ReplyMessage(msg *discordgo.MessageSend) error {
+	return c.send(msg, c.Ephemeral)
+}
+
+// ReplyError reports a failed command. Slash command errors are only shown
+// to the invoker.
+func (c *Context) ReplyError(err error) error {
+	return c.send(&discordgo.MessageSend{Content: fmt.Sprintf("Error executing command: %v", err)}, true)
+}
+
+// Defer acknowledges a slash command that may take longer than Discord's
+// three second response window. Later replies are sent as follow-ups.
+func (c *Context) Defer() error {
+	if c.Interaction == nil {
+		return c.Session.ChannelTyping(c.ChannelID)
+	}
+	if c.responded {
+		return nil
+	}
+
+	c.responded = true
+	return c.Session.InteractionRespond(c.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Flags: c.flags(c.Ephemeral)},
+	})
+}
+
+// send delivers a reply to the channel or interaction
+func (c *Context) send(msg *discordgo.MessageSend, ephemeral bool) error {
+	if c.Interaction == nil {
+		_, err := c.Session.ChannelMessageSendComplex(c.ChannelID, msg)
+		return err
+	}
+
+	// The first reply answers the interaction, later ones follow up
+	if !c.responded {
+		c.responded = true
+		return c.Session.InteractionRespond(c.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content:    msg.Content,
+				Embeds:     msg.Embeds,
+				Components: msg.Components,
+				Flags:      c.flags(ephemeral),
+			},
+		})
+	}
+
+	_, err := c.Session.FollowupMessageCreate(c.Interaction, true, &discordgo.WebhookParams{
+		Content:    msg.Content,
+		Embeds:     msg.Embeds,
+		Components: msg.Components,
+		Flags:      c.flags(ephemeral),
+	})
+	return err
+}
+
+// flags returns the message flags for a reply
+func (c *Context) flags(ephemeral bool) discordgo.MessageFlags {
+	if ephemeral {
+		return discordgo.MessageFlagsEphemeral
+	}
+	return 0
+}
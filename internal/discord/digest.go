@@ -0,0 +1,255 @@
+// internal/discord/digest.go
+package discord
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/config"
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+	"github.com/NullMeDev/Infopulse-Node/internal/scheduler"
+	"github.com/bwmarrin/discordgo"
+)
+
+// digestPostAction is the permission check and audit entry of posting a
+// digest outside its schedule
+var digestPostAction = &Command{Name: "digest-post", Permission: PermissionOperator}
+
+// defaultDigest is previewed when no digests are configured
+var defaultDigest = config.DigestConfig{
+	Name:        "daily",
+	Schedule:    "@daily",
+	Timezone:    "UTC",
+	WindowHours: 24,
+	TopN:        5,
+	Categories:  models.Categories,
+}
+
+// scheduledDigest is a configured digest with its parsed schedule
+type scheduledDigest struct {
+	digest config.DigestConfig
+	cron   *scheduler.Cron
+	loc    *time.Location
+	next   time.Time
+}
+
+// digestLoop posts each configured digest on its schedule
+func (b *Bot) digestLoop() {
+	defer b.wg.Done()
+
+	var digests []*scheduledDigest
+	now := time.Now()
+	for _, digest := range b.config.Digests {
+		// Both were checked when the config was loaded
+		cron, err := scheduler.ParseCron(digest.Schedule)
+		if err != nil {
+			b.logger.Error("Bot", fmt.Sprintf("Skipping digest %s: %v", digest.Name, err))
+			continue
+		}
+		loc, err := time.LoadLocation(digest.Timezone)
+		if err != nil {
+			b.logger.Error("Bot", fmt.Sprintf("Skipping digest %s: %v", digest.Name, err))
+			continue
+		}
+
+		next := cron.Next(now.In(loc))
+		if next.IsZero() {
+			b.logger.Warning("Bot", fmt.Sprintf("Digest %s schedule %q never runs", digest.Name, digest.Schedule))
+			continue
+		}
+		b.logger.Info("Bot", fmt.Sprintf("Digest %s next posts at %s", digest.Name, next.Format(time.RFC3339)))
+		digests = append(digests, &scheduledDigest{digest: digest, cron: cron, loc: loc, next: next})
+	}
+
+	for len(digests) > 0 {
+		next := digests[0].next
+		for _, d := range digests[1:] {
+			if d.next.Before(next) {
+				next = d.next
+			}
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-timer.C:
+		case <-b.stopChan:
+			timer.Stop()
+			b.logger.Info("Bot", "Digest loop stopped")
+			return
+		}
+
+		now := time.Now()
+		remaining := digests[:0]
+		for _, d := range digests {
+			if d.next.After(now) {
+				remaining = append(remaining, d)
+				continue
+			}
+
+			if err := b.postDigest(d.digest, d.digest.ChannelID); err != nil {
+				b.logger.Error("Bot", fmt.Sprintf("Failed to post digest %s: %v", d.digest.Name, err))
+			}
+
+			if d.next = d.cron.Next(now.In(d.loc)); !d.next.IsZero() {
+				remaining = append(remaining, d)
+			}
+		}
+		digests = remaining
+	}
+
+	<-b.stopChan
+	b.logger.Info("Bot", "Digest loop stopped")
+}
+
+// postDigest builds a digest and posts it to a channel
+func (b *Bot) postDigest(digest config.DigestConfig, channelID string) error {
+	built, err := b.engine.BuildDigest(digest, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	for _, embeds := range splitEmbeds(createDigestEmbeds(built, digest.Timezone)) {
+		_, err := b.session.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{Embeds: embeds})
+		if err != nil {
+			return fmt.Errorf("failed to send digest: %v", err)
+		}
+	}
+
+	b.logger.Info("Bot", fmt.Sprintf("Posted digest %s with %d categories to channel %s",
+		digest.Name, len(built.Sections), channelID))
+	return nil
+}
+
+// digestCommand handles the digest command
+func (b *Bot) digestCommand(ctx *Context, args Args) error {
+	digest, err := b.findDigest(args.String("name"))
+	if err != nil {
+		return err
+	}
+
+	switch action := strings.ToLower(args.String("action")); action {
+	case "preview":
+		// Ranking a week of items can outlast the slash command response window
+		if err := ctx.Defer(); err != nil {
+			return err
+		}
+
+		built, err := b.engine.BuildDigest(digest, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+		for _, embeds := range splitEmbeds(createDigestEmbeds(built, digest.Timezone)) {
+			if err := ctx.ReplyMessage(&discordgo.MessageSend{Embeds: embeds}); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case "post":
+		if digest.ChannelID == "" {
+			return fmt.Errorf("digest %s has no channel configured", digest.Name)
+		}
+
+		allowed := b.permissionLevel(ctx) >= digestPostAction.Permission
+		b.audit(ctx, digestPostAction, Args{"name": digest.Name, "channel": digest.ChannelID}, allowed)
+		if !allowed {
+			return fmt.Errorf("you need %s access to post digests", digestPostAction.Permission)
+		}
+
+		if err := ctx.Defer(); err != nil {
+			return err
+		}
+		if err := b.postDigest(digest, digest.ChannelID); err != nil {
+			return err
+		}
+		return ctx.Replyf("Posted digest %s to <#%s>.", digest.Name, digest.ChannelID)
+
+	default:
+		return fmt.Errorf("unknown action %q, expected preview or post", action)
+	}
+}
+
+// findDigest returns a configured digest by name. Without a name it
+// returns the first configured digest, or a default daily digest.
+func (b *Bot) findDigest(name string) (config.DigestConfig, error) {
+	if name == "" {
+		if len(b.config.Digests) > 0 {
+			return b.config.Digests[0], nil
+		}
+		return defaultDigest, nil
+	}
+
+	names := make([]string, 0, len(b.config.Digests))
+	for _, digest := range b.config.Digests {
+		if strings.EqualFold(digest.Name, name) {
+			return digest, nil
+		}
+		names = append(names, digest.Name)
+	}
+
+	if len(names) == 0 {
+		return config.DigestConfig{}, fmt.Errorf("no digests are configured; leave out the name to preview the default daily digest")
+	}
+	return config.DigestConfig{}, fmt.Errorf("unknown digest %q, expected one of %s", name, strings.Join(names, ", "))
+}
+
+// createDigestEmbeds creates a digest's header embed followed by an embed
+// for each category. The header's date is shown in the digest's time zone.
+func createDigestEmbeds(digest *models.Digest, timezone string) []*discordgo.MessageEmbed {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	header := &discordgo.MessageEmbed{
+		Title:     truncate(fmt.Sprintf("Infopulse %s digest: %s", digest.Name, digest.Until.In(loc).Format("Mon 2 Jan 2006")), maxEmbedTitle),
+		Color:     0x00aaff,
+		Timestamp: digest.Until.UTC().Format(time.RFC3339),
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Infopulse Node v1.0",
+		},
+	}
+
+	lines := []string{fmt.Sprintf("Top stories from %s to %s.", discordTimestamp(digest.Since), discordTimestamp(digest.Until))}
+	if len(digest.Sections) == 0 {
+		lines = append(lines, "No new intelligence in this period.")
+	}
+	for _, section := range digest.Sections {
+		lines = append(lines, fmt.Sprintf("**%s**: top %d of %d", section.Category, len(section.Items), section.Total))
+	}
+	header.Description = truncateLines(lines, maxEmbedDescription)
+
+	embeds := []*discordgo.MessageEmbed{header}
+	for _, section := range digest.Sections {
+		embed := createIntelEmbed(fmt.Sprintf("%s (top %d of %d)", section.Category, len(section.Items), section.Total), section.Items, false)
+		embed.Color = severityColor(section.Items[0].Severity)
+		embed.Footer = nil
+		embeds = append(embeds, embed)
+	}
+
+	return embeds
+}
+
+// splitEmbeds groups embeds into messages within Discord's limits on the
+// number of embeds and total characters of a message
+func splitEmbeds(embeds []*discordgo.MessageEmbed) [][]*discordgo.MessageEmbed {
+	var messages [][]*discordgo.MessageEmbed
+	var current []*discordgo.MessageEmbed
+	length := 0
+
+	for _, embed := range embeds {
+		embedLen := embedLength(embed)
+		if len(current) > 0 && (len(current) >= maxMessageEmbeds || length+embedLen > maxEmbedTotal) {
+			messages = append(messages, current)
+			current, length = nil, 0
+		}
+		current = append(current, embed)
+		length += embedLen
+	}
+	if len(current) > 0 {
+		messages = append(messages, current)
+	}
+
+	return messages
+}
@@ -1 +1,370 @@
+// internal/discord/embeds.go
+package discord
 
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+	"github.com/bwmarrin/discordgo"
+)
+
+// Discord embed limits
+const (
+	maxEmbedTitle       = 256
+	maxEmbedDescription = 4096
+	maxEmbedFields      = 25
+	maxFieldName        = 256
+	maxFieldValue       = 1024
+	maxEmbedTotal       = 6000 // Characters across all text of an embed, or of all embeds of a message
+	maxMessageEmbeds    = 10   // Embeds in one message
+)
+
+// Summary lengths in item lists
+const (
+	compactSummaryLength  = 200
+	expandedSummaryLength = 900
+)
+
+// createIntelEmbed creates an embed listing intelligence items. Expanded
+// lists show longer summaries and each item's categories. Items are added
+// until the embed is full, so fewer than len(items) fields may be shown.
+func createIntelEmbed(title string, items []*models.Intelligence, expanded bool) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:     truncate(title, maxEmbedTitle),
+		Color:     0x00aaff,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Infopulse Node v1.0",
+		},
+	}
+
+	if len(items) == 0 {
+		embed.Description = "No intelligence items found."
+		return embed
+	}
+
+	summaryLength := compactSummaryLength
+	if expanded {
+		summaryLength = expandedSummaryLength
+	}
+
+	length := embedLength(embed)
+	for _, item := range items {
+		if len(embed.Fields) >= maxEmbedFields {
+			break
+		}
+
+		name := item.Title
+		if item.Severity != "" {
+			name = fmt.Sprintf("[%s] %s", item.Severity, name)
+		}
+		if item.Exploited {
+			name = "[EXPLOITED] " + name
+		}
+
+		value := fmt.Sprintf("%s\n[Link](%s) | ID: `%s` | %s",
+			truncate(item.Summary, summaryLength), item.URL, item.ID, item.Published.Format("2006-01-02"))
+		if expanded {
+			value += "\nCategories: " + formatCategories(item)
+		}
+		if len(item.Alternates) > 0 {
+			value += "\nAlso reported by: " + alsoReportedBy(item.Alternates, ", ")
+		}
+
+		field := &discordgo.MessageEmbedField{
+			Name:  truncate(name, maxFieldName),
+			Value: truncate(value, maxFieldValue),
+		}
+		fieldLength := utf8.RuneCountInString(field.Name) + utf8.RuneCountInString(field.Value)
+		if length+fieldLength > maxEmbedTotal {
+			break
+		}
+
+		embed.Fields = append(embed.Fields, field)
+		length += fieldLength
+	}
+
+	return embed
+}
+
+// embedLength counts the characters of an embed that Discord limits in total
+func embedLength(embed *discordgo.MessageEmbed) int {
+	length := utf8.RuneCountInString(embed.Title) + utf8.RuneCountInString(embed.Description)
+	if embed.Footer != nil {
+		length += utf8.RuneCountInString(embed.Footer.Text)
+	}
+	if embed.Author != nil {
+		length += utf8.RuneCountInString(embed.Author.Name)
+	}
+	for _, field := range embed.Fields {
+		length += utf8.RuneCountInString(field.Name) + utf8.RuneCountInString(field.Value)
+	}
+	return length
+}
+
+// createItemEmbed creates an embed for a single intelligence item
+func createItemEmbed(item *models.Intelligence) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:       truncate(item.Title, maxEmbedTitle),
+		URL:         item.URL,
+		Description: truncate(item.Summary, maxEmbedDescription),
+		Color:       severityColor(item.Severity),
+		Timestamp:   item.Published.UTC().Format(time.RFC3339),
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:   "Category",
+				Value:  formatCategories(item),
+				Inline: true,
+			},
+			{
+				Name:   "Source",
+				Value:  item.SourceID,
+				Inline: true,
+			},
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("ID: %s", item.ID),
+		},
+	}
+
+	if item.Severity != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Severity",
+			Value:  item.Severity,
+			Inline: true,
+		})
+	}
+
+	if item.Exploited {
+		embed.Color = 0x8b0000
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Actively Exploited",
+			Value:  "Listed in the CISA Known Exploited Vulnerabilities catalog",
+			Inline: false,
+		})
+	}
+
+	if item.CVEID != "" {
+		value := item.CVEID
+		if item.CVSSScore > 0 {
+			value = fmt.Sprintf("%s (CVSS %.1f)", item.CVEID, item.CVSSScore)
+		}
+		if item.CVSSVector != "" {
+			value += fmt.Sprintf("\n`%s`", item.CVSSVector)
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "CVE",
+			Value: truncate(value, maxFieldValue),
+		})
+	}
+
+	if len(item.Aliases) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "Aliases",
+			Value: truncate(strings.Join(item.Aliases, ", "), maxFieldValue),
+		})
+	}
+
+	if len(item.Affected) > 0 {
+		var lines []string
+		for _, pkg := range item.Affected {
+			var ranges []string
+			for _, r := range pkg.Ranges {
+				ranges = append(ranges, r.String())
+			}
+			line := fmt.Sprintf("%s `%s`", pkg.Ecosystem, pkg.Name)
+			if len(ranges) > 0 {
+				line += ": " + strings.Join(ranges, "; ")
+			}
+			lines = append(lines, line)
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "Affected Packages",
+			Value: truncate(strings.Join(lines, "\n"), maxFieldValue),
+		})
+	}
+
+	if item.Version != "" {
+		value := item.Version
+		if item.Prerelease {
+			value += " (pre-release)"
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Version",
+			Value:  truncate(value, maxFieldValue),
+			Inline: true,
+		})
+	}
+
+	if len(item.CPEs) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "Affected Platforms",
+			Value: truncate("`"+strings.Join(item.CPEs, "`\n`")+"`", maxFieldValue),
+		})
+	}
+
+	if len(item.Alternates) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "Also Reported By",
+			Value: truncate(alsoReportedBy(item.Alternates, "\n"), maxFieldValue),
+		})
+	}
+
+	return embed
+}
+
+// createDetailEmbed creates the full view of an intelligence item with its
+// fetch times and related items
+func createDetailEmbed(item *models.Intelligence, related []*models.Intelligence) *discordgo.MessageEmbed {
+	embed := createItemEmbed(item)
+
+	embed.Fields = append(embed.Fields,
+		&discordgo.MessageEmbedField{
+			Name:   "Published",
+			Value:  discordTimestamp(item.Published),
+			Inline: true,
+		},
+		&discordgo.MessageEmbedField{
+			Name:   "Retrieved",
+			Value:  discordTimestamp(item.Retrieved),
+			Inline: true,
+		},
+	)
+
+	if len(related) > 0 {
+		lines := make([]string, 0, len(related))
+		for _, r := range related {
+			lines = append(lines, fmt.Sprintf("[%s](%s) | %s | `%s`",
+				truncate(r.Title, 80), r.URL, r.SourceID, r.ID))
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "Related Items",
+			Value: truncateLines(lines, maxFieldValue),
+		})
+	}
+
+	if len(embed.Fields) > maxEmbedFields {
+		embed.Fields = embed.Fields[:maxEmbedFields]
+	}
+	fitEmbed(embed)
+
+	return embed
+}
+
+// discordTimestamp formats a time for Discord to show in the reader's time zone
+func discordTimestamp(t time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+	return fmt.Sprintf("<t:%d:f>", t.Unix())
+}
+
+// truncateLines joins as many whole lines as fit in max runes
+func truncateLines(lines []string, max int) string {
+	var kept []string
+	length := 0
+	for _, line := range lines {
+		lineLength := utf8.RuneCountInString(line) + 1
+		if length+lineLength > max {
+			break
+		}
+		kept = append(kept, line)
+		length += lineLength
+	}
+	if len(kept) == 0 && len(lines) > 0 {
+		return truncate(lines[0], max)
+	}
+	return strings.Join(kept, "\n")
+}
+
+// fitEmbed keeps an embed within Discord's total length limit by
+// shortening its description, then dropping fields from the end
+func fitEmbed(embed *discordgo.MessageEmbed) {
+	excess := embedLength(embed) - maxEmbedTotal
+	if excess <= 0 {
+		return
+	}
+
+	length := utf8.RuneCountInString(embed.Description)
+	if excess < length {
+		embed.Description = truncate(embed.Description, length-excess)
+		return
+	}
+	embed.Description = ""
+
+	for len(embed.Fields) > 0 && embedLength(embed) > maxEmbedTotal {
+		embed.Fields = embed.Fields[:len(embed.Fields)-1]
+	}
+}
+
+// alsoReportedBy links to the other reports of a story by source
+func alsoReportedBy(alternates []models.Alternate, separator string) string {
+	links := make([]string, 0, len(alternates))
+	for _, alternate := range alternates {
+		links = append(links, fmt.Sprintf("[%s](%s)", alternate.SourceID, alternate.URL))
+	}
+	return strings.Join(links, separator)
+}
+
+// createWatchAlertEmbed creates an embed for an advisory affecting watched packages
+func createWatchAlertEmbed(alert *models.WatchAlert) *discordgo.MessageEmbed {
+	embed := createItemEmbed(alert.Item)
+	embed.Title = truncate("Dependency Alert: "+alert.Item.Title, maxEmbedTitle)
+	embed.Color = 0xff0000
+
+	// Lead with the matched packages
+	field := &discordgo.MessageEmbedField{
+		Name:  "Watched Packages",
+		Value: truncate("`"+strings.Join(alert.Packages, "`\n`")+"`", maxFieldValue),
+	}
+	embed.Fields = append([]*discordgo.MessageEmbedField{field}, embed.Fields...)
+	if len(embed.Fields) > maxEmbedFields {
+		embed.Fields = embed.Fields[:maxEmbedFields]
+	}
+
+	return embed
+}
+
+// severityColor returns an embed color for a severity level
+func severityColor(severity string) int {
+	switch severity {
+	case "CRITICAL":
+		return 0x8b0000
+	case "HIGH":
+		return 0xff0000
+	case "MEDIUM":
+		return 0xffa500
+	case "LOW":
+		return 0xffff00
+	default:
+		return 0x00aaff
+	}
+}
+
+// truncate shortens text to at most max runes
+func truncate(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	if max <= 3 {
+		return string(runes[:max])
+	}
+	return string(runes[:max-3]) + "..."
+}
+
+// formatCategories lists the primary category followed by any secondary
+// categories with their confidence
+func formatCategories(item *models.Intelligence) string {
+	parts := []string{string(item.Category)}
+	for _, category := range item.Categories {
+		if category.Category == item.Category {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s (%.0f%%)", category.Category, category.Confidence*100))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,189 @@
+// internal/discord/intel.go
+package discord
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// intelPrefix starts the custom ID of item action components
+	intelPrefix = "intel"
+	// maxRelatedItems is the number of related items in a detail view
+	maxRelatedItems = 5
+)
+
+// repostAction is the permission check and audit entry of reposting an item
+var repostAction = &Command{Name: "repost", Permission: PermissionOperator}
+
+// intelCommand handles the intel command
+func (b *Bot) intelCommand(ctx *Context, args Args) error {
+	id := strings.ToLower(strings.Trim(args.String("id"), "`"))
+
+	item, err := b.engine.FindIntel(id)
+	if err != nil {
+		return err
+	}
+	if item == nil {
+		return ctx.Replyf("No intelligence item found with ID `%s`. IDs are shown in list results and embed footers.", id)
+	}
+
+	related, err := b.engine.GetRelatedIntel(item, maxRelatedItems)
+	if err != nil {
+		b.logger.Error("Bot", fmt.Sprintf("Failed to load related items for %s: %v", item.ID, err))
+	}
+
+	return ctx.ReplyMessage(&discordgo.MessageSend{
+		Embeds:     []*discordgo.MessageEmbed{createDetailEmbed(item, related)},
+		Components: itemComponents(item),
+	})
+}
+
+// itemComponents returns the action buttons of an item's detail view
+func itemComponents(item *models.Intelligence) []discordgo.MessageComponent {
+	buttons := []discordgo.MessageComponent{
+		discordgo.Button{
+			Label:    "Bookmark",
+			Style:    discordgo.PrimaryButton,
+			CustomID: intelCustomID("bookmark", item.ID),
+		},
+		discordgo.Button{
+			Label:    "Mark Read",
+			Style:    discordgo.SecondaryButton,
+			CustomID: intelCustomID("read", item.ID),
+		},
+		discordgo.Button{
+			Label:    "Repost",
+			Style:    discordgo.SecondaryButton,
+			CustomID: intelCustomID("repost", item.ID),
+		},
+	}
+
+	// Link buttons only accept absolute URLs
+	if strings.HasPrefix(item.URL, "http://") || strings.HasPrefix(item.URL, "https://") {
+		buttons = append(buttons, discordgo.Button{
+			Label: "Open Original",
+			Style: discordgo.LinkButton,
+			URL:   item.URL,
+		})
+	}
+
+	return []discordgo.MessageComponent{discordgo.ActionsRow{Components: buttons}}
+}
+
+// intelCustomID identifies an action on an item
+func intelCustomID(action, id string) string {
+	return strings.Join([]string{intelPrefix, action, id}, ":")
+}
+
+// handleIntelAction handles the buttons and channel picker of a detail view.
+// Replies are only shown to the user who clicked.
+func (b *Bot) handleIntelAction(s *discordgo.Session, i *discordgo.InteractionCreate, action, id string) {
+	ctx := newInteractionContext(s, i, true)
+	if ctx.User == nil {
+		return
+	}
+
+	item, err := b.engine.FindIntel(id)
+	if err == nil && item == nil {
+		err = fmt.Errorf("item %s no longer exists", id)
+	}
+	if err != nil {
+		ctx.ReplyError(err)
+		return
+	}
+
+	switch action {
+	case "bookmark":
+		bookmarked, err := b.engine.ToggleBookmark(ctx.User.ID, item.ID)
+		if err != nil {
+			ctx.ReplyError(err)
+			return
+		}
+		if bookmarked {
+			ctx.Replyf("Bookmarked `%s`. Use %sbookmarks to see your bookmarks.", item.ID, b.config.CommandPrefix)
+		} else {
+			ctx.Replyf("Removed the bookmark on `%s`.", item.ID)
+		}
+
+	case "read":
+		if err := b.engine.MarkRead(ctx.User.ID, item.ID); err != nil {
+			ctx.ReplyError(err)
+			return
+		}
+		ctx.Replyf("Marked `%s` as read. It is left out of lists run with --unread.", item.ID)
+
+	case "repost":
+		if b.permissionLevel(ctx) < repostAction.Permission {
+			ctx.ReplyError(fmt.Errorf("you need %s access to repost items", repostAction.Permission))
+			return
+		}
+		ctx.ReplyMessage(&discordgo.MessageSend{
+			Content: fmt.Sprintf("Choose a channel to repost `%s` to.", item.ID),
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.SelectMenu{
+							MenuType:     discordgo.ChannelSelectMenu,
+							CustomID:     intelCustomID("repostto", item.ID),
+							Placeholder:  "Channel",
+							ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText, discordgo.ChannelTypeGuildNews},
+						},
+					},
+				},
+			},
+		})
+
+	case "repostto":
+		values := i.MessageComponentData().Values
+		if len(values) == 0 {
+			return
+		}
+		channelID := values[0]
+
+		allowed := b.permissionLevel(ctx) >= repostAction.Permission
+		b.audit(ctx, repostAction, Args{"id": item.ID, "channel": channelID}, allowed)
+		if !allowed {
+			ctx.ReplyError(fmt.Errorf("you need %s access to repost items", repostAction.Permission))
+			return
+		}
+
+		content := fmt.Sprintf("Reposted `%s` to <#%s>.", item.ID, channelID)
+		_, err := s.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+			Embeds:     []*discordgo.MessageEmbed{createItemEmbed(item)},
+			Components: itemComponents(item),
+		})
+		if err != nil {
+			b.logger.Error("Bot", fmt.Sprintf("Failed to repost %s to channel %s: %v", item.ID, channelID, err))
+			content = fmt.Sprintf("Could not post to <#%s>: %v", channelID, err)
+		}
+
+		// Replace the channel picker with the outcome
+		err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseUpdateMessage,
+			Data: &discordgo.InteractionResponseData{
+				Content:    content,
+				Components: []discordgo.MessageComponent{},
+			},
+		})
+		if err != nil {
+			b.logger.Error("Bot", fmt.Sprintf("Failed to respond to repost: %v", err))
+		}
+	}
+}
+
+// bookmarksCommand handles the bookmarks command
+func (b *Bot) bookmarksCommand(ctx *Context, args Args) error {
+	if ctx.User == nil {
+		return fmt.Errorf("could not identify the user")
+	}
+	userID := ctx.User.ID
+
+	fetch := func(start pageStart, limit int) ([]*models.Intelligence, error) {
+		return b.engine.GetBookmarks(userID, limit, start.offset)
+	}
+	return b.replyPages(ctx, "Your Bookmarks", args.Int("count"), fetch)
+}
@@ -0,0 +1,160 @@
+// internal/discord/interactions.go
+package discord
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+	"github.com/bwmarrin/discordgo"
+)
+
+// maxAutocompleteChoices is the most choices Discord accepts in an autocomplete reply
+const maxAutocompleteChoices = 25
+
+// registerSlashCommands registers the command table as application commands,
+// replacing any previously registered ones. Commands are registered in a
+// single guild if one is configured, since global commands take a while to
+// propagate.
+func (b *Bot) registerSlashCommands() error {
+	var commands []*discordgo.ApplicationCommand
+	for _, cmd := range b.commands.Commands() {
+		commands = append(commands, &discordgo.ApplicationCommand{
+			Name:        cmd.Name,
+			Description: truncate(cmd.Description, 100),
+			Options:     slashOptions(cmd),
+		})
+	}
+
+	registered, err := b.session.ApplicationCommandBulkOverwrite(b.session.State.User.ID, b.config.SlashCommandGuildID, commands)
+	if err != nil {
+		return err
+	}
+
+	b.logger.Info("Bot", fmt.Sprintf("Registered %d slash commands", len(registered)))
+	return nil
+}
+
+// interactionHandler handles slash commands, option autocompletion and
+// button clicks
+func (b *Bot) interactionHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand:
+		b.handleSlashCommand(s, i)
+	case discordgo.InteractionApplicationCommandAutocomplete:
+		b.handleAutocomplete(s, i)
+	case discordgo.InteractionMessageComponent:
+		b.handleComponent(s, i)
+	}
+}
+
+// handleComponent routes button clicks and menu selections by the prefix
+// of their custom ID
+func (b *Bot) handleComponent(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	parts := strings.Split(i.MessageComponentData().CustomID, ":")
+	if len(parts) != 3 {
+		return
+	}
+
+	switch parts[0] {
+	case pagerPrefix:
+		b.handlePagerButton(s, i, parts[1], parts[2])
+	case intelPrefix:
+		b.handleIntelAction(s, i, parts[1], parts[2])
+	}
+}
+
+// handleSlashCommand runs a slash command through the command table
+func (b *Bot) handleSlashCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	data := i.ApplicationCommandData()
+
+	cmd, exists := b.commands.Lookup(data.Name)
+	if !exists {
+		ctx := newInteractionContext(s, i, true)
+		ctx.ReplyError(fmt.Errorf("unknown command: %s", data.Name))
+		return
+	}
+	ctx := newInteractionContext(s, i, cmd.Ephemeral)
+
+	// Collect option values as the text a prefix command would receive,
+	// and attachments as a message would carry them
+	raw := make(map[*Arg]string)
+	for _, option := range data.Options {
+		arg := cmd.arg(option.Name)
+		if arg == nil {
+			continue
+		}
+
+		if arg.Type == ArgAttachment {
+			id, _ := option.Value.(string)
+			if data.Resolved != nil {
+				if attachment, ok := data.Resolved.Attachments[id]; ok {
+					ctx.Attachments = append(ctx.Attachments, attachment)
+				}
+			}
+			continue
+		}
+		raw[arg] = fmt.Sprintf("%v", option.Value)
+	}
+
+	args, err := b.bindArgs(cmd, raw)
+	if err != nil {
+		ctx.ReplyError(err)
+		return
+	}
+
+	b.runCommand(ctx, cmd, args)
+}
+
+// handleAutocomplete suggests values for category and source options
+func (b *Bot) handleAutocomplete(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	data := i.ApplicationCommandData()
+	cmd, exists := b.commands.Lookup(data.Name)
+	if !exists {
+		return
+	}
+
+	var arg *Arg
+	var focused *discordgo.ApplicationCommandInteractionDataOption
+	for _, option := range data.Options {
+		if option.Focused {
+			focused = option
+			arg = cmd.arg(option.Name)
+			break
+		}
+	}
+	if arg == nil {
+		return
+	}
+
+	typed := strings.ToLower(focused.StringValue())
+	var choices []*discordgo.ApplicationCommandOptionChoice
+	add := func(name, value string) {
+		if len(choices) >= maxAutocompleteChoices {
+			return
+		}
+		if typed != "" && !strings.Contains(strings.ToLower(name), typed) && !strings.Contains(strings.ToLower(value), typed) {
+			return
+		}
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: truncate(name, 100), Value: value})
+	}
+
+	switch arg.Type {
+	case ArgCategory:
+		for _, category := range models.Categories {
+			add(string(category), string(category))
+		}
+	case ArgSource:
+		for _, source := range b.config.FeedSources {
+			add(fmt.Sprintf("%s (%s)", source.Name, source.ID), source.ID)
+		}
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{Choices: choices},
+	})
+	if err != nil {
+		b.logger.Error("Bot", fmt.Sprintf("Failed to send autocomplete choices: %v", err))
+	}
+}
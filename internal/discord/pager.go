@@ -0,0 +1,241 @@
+// internal/discord/pager.go
+package discord
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/feeds"
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// pagerTTL is how long results can be paged after they were last used
+	pagerTTL = 15 * time.Minute
+	// pagerPrefix starts the custom ID of pager buttons
+	pagerPrefix = "page"
+)
+
+// pageStart is where a page of results begins. Lists continue after the
+// last item shown; ranked search results continue from an offset.
+type pageStart struct {
+	after  *feeds.Cursor
+	offset int
+}
+
+// pageFetcher returns up to limit results from a page start
+type pageFetcher func(start pageStart, limit int) ([]*models.Intelligence, error)
+
+// pager is the state of a paginated result message
+type pager struct {
+	id       string
+	userID   string
+	title    string
+	size     int
+	fetch    pageFetcher
+	starts   []pageStart // Start of each page reached so far
+	page     int
+	expanded bool
+	expires  time.Time
+}
+
+// replyPages replies with the first page of results and buttons to move
+// between pages
+func (b *Bot) replyPages(ctx *Context, title string, size int, fetch pageFetcher) error {
+	id, err := newPagerID()
+	if err != nil {
+		return err
+	}
+
+	p := &pager{
+		id:      id,
+		title:   title,
+		size:    size,
+		fetch:   fetch,
+		starts:  []pageStart{{}},
+		expires: time.Now().Add(pagerTTL),
+	}
+	if ctx.User != nil {
+		p.userID = ctx.User.ID
+	}
+
+	embed, components, err := p.render()
+	if err != nil {
+		return err
+	}
+
+	b.pagersMu.Lock()
+	b.prunePagers()
+	b.pagers[p.id] = p
+	b.pagersMu.Unlock()
+
+	return ctx.ReplyMessage(&discordgo.MessageSend{
+		Embeds:     []*discordgo.MessageEmbed{embed},
+		Components: components,
+	})
+}
+
+// handlePagerButton moves a result message to another page or toggles
+// its expanded view
+func (b *Bot) handlePagerButton(s *discordgo.Session, i *discordgo.InteractionCreate, id, action string) {
+	userID := ""
+	if i.Member != nil && i.Member.User != nil {
+		userID = i.Member.User.ID
+	} else if i.User != nil {
+		userID = i.User.ID
+	}
+
+	b.pagersMu.Lock()
+	defer b.pagersMu.Unlock()
+
+	b.prunePagers()
+	p, exists := b.pagers[id]
+	if !exists {
+		b.respondEphemeral(s, i, "These results have expired. Run the command again to page through them.")
+		return
+	}
+	if p.userID != "" && p.userID != userID {
+		b.respondEphemeral(s, i, "Only the person who ran the command can page through these results.")
+		return
+	}
+
+	switch action {
+	case "prev":
+		if p.page > 0 {
+			p.page--
+		}
+	case "next":
+		if p.page+1 < len(p.starts) {
+			p.page++
+		}
+	case "expand":
+		// Expanded items take more room, so later pages start elsewhere
+		p.expanded = !p.expanded
+		p.starts = p.starts[:p.page+1]
+	}
+	p.expires = time.Now().Add(pagerTTL)
+
+	embed, components, err := p.render()
+	if err != nil {
+		b.logger.Error("Bot", fmt.Sprintf("Failed to render results page: %v", err))
+		b.respondEphemeral(s, i, fmt.Sprintf("Error loading results: %v", err))
+		return
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{embed},
+			Components: components,
+		},
+	})
+	if err != nil {
+		b.logger.Error("Bot", fmt.Sprintf("Failed to update results page: %v", err))
+	}
+}
+
+// respondEphemeral answers an interaction with a message only the user sees
+func (b *Bot) respondEphemeral(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		b.logger.Error("Bot", fmt.Sprintf("Failed to respond to interaction: %v", err))
+	}
+}
+
+// prunePagers forgets expired result messages. The caller holds pagersMu.
+func (b *Bot) prunePagers() {
+	now := time.Now()
+	for id, p := range b.pagers {
+		if now.After(p.expires) {
+			delete(b.pagers, id)
+		}
+	}
+}
+
+// render builds the embed and buttons of the current page, recording where
+// the next page starts
+func (p *pager) render() (*discordgo.MessageEmbed, []discordgo.MessageComponent, error) {
+	start := p.starts[p.page]
+
+	// Fetch one extra item to tell whether there is another page
+	items, err := p.fetch(start, p.size+1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	page := items
+	if len(page) > p.size {
+		page = page[:p.size]
+	}
+
+	title := fmt.Sprintf("%s (page %d)", p.title, p.page+1)
+	embed := createIntelEmbed(title, page, p.expanded)
+
+	// The embed may not fit every item; the next page starts after the
+	// last one shown
+	shown := len(embed.Fields)
+	more := shown > 0 && shown < len(items)
+	p.starts = p.starts[:p.page+1]
+	if more {
+		p.starts = append(p.starts, pageStart{
+			after:  feeds.CursorAfter(items[shown-1]),
+			offset: start.offset + shown,
+		})
+	}
+
+	expandLabel := "Expand"
+	if p.expanded {
+		expandLabel = "Collapse"
+	}
+	components := []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Prev",
+					Style:    discordgo.SecondaryButton,
+					CustomID: p.customID("prev"),
+					Disabled: p.page == 0,
+				},
+				discordgo.Button{
+					Label:    "Next",
+					Style:    discordgo.SecondaryButton,
+					CustomID: p.customID("next"),
+					Disabled: !more,
+				},
+				discordgo.Button{
+					Label:    expandLabel,
+					Style:    discordgo.PrimaryButton,
+					CustomID: p.customID("expand"),
+					Disabled: shown == 0,
+				},
+			},
+		},
+	}
+
+	return embed, components, nil
+}
+
+// customID identifies a button of this pager
+func (p *pager) customID(action string) string {
+	return strings.Join([]string{pagerPrefix, p.id, action}, ":")
+}
+
+// newPagerID generates a random pager ID, so buttons left on messages from
+// before a restart cannot control new results
+func newPagerID() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate pager ID: %v", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,467 @@
+// internal/discord/registry.go
+package discord
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+	"github.com/bwmarrin/discordgo"
+)
+
+// Permission is the access level a command requires
+type Permission int
+
+const (
+	// PermissionEveryone allows anyone to run a command
+	PermissionEveryone Permission = iota
+	// PermissionOperator allows operators and administrators
+	PermissionOperator
+	// PermissionAdmin restricts a command to bot administrators
+	PermissionAdmin
+)
+
+// String returns the name of a permission level
+func (p Permission) String() string {
+	switch p {
+	case PermissionOperator:
+		return "operator"
+	case PermissionAdmin:
+		return "admin"
+	default:
+		return "everyone"
+	}
+}
+
+// ArgType is the type of a command argument
+type ArgType int
+
+const (
+	ArgString     ArgType = iota // A word, or several words in quotes
+	ArgInt                       // A whole number
+	ArgBool                      // A switch, given as --name
+	ArgCategory                  // An intelligence category
+	ArgSource                    // A configured feed source ID
+	ArgDuration                  // A duration such as 90m, 24h or 7d
+	ArgAttachment                // A file attached to the message
+	ArgText                      // The rest of the positional words
+)
+
+// Arg declares an argument of a command
+type Arg struct {
+	Name        string
+	Type        ArgType
+	Description string
+	Required    bool
+	// Flag arguments are only given as --name value. Other arguments are
+	// positional but may also be given as flags.
+	Flag    bool
+	Aliases []string // Other names of the flag
+	Default string   // Value used when the argument is not given
+	Min     int      // Smallest value of an ArgInt, if Max is set
+	Max     int      // Largest value of an ArgInt (0 for no limit)
+}
+
+// Command is an entry in the command table shared by prefix and slash commands
+type Command struct {
+	Name        string
+	Aliases     []string
+	Description string
+	Args        []*Arg
+	Permission  Permission
+	// Ephemeral shows slash command replies only to the invoker
+	Ephemeral bool
+	Handler   CommandHandler
+}
+
+// CommandHandler is a function that handles a command
+type CommandHandler func(ctx *Context, args Args) error
+
+// Args holds the parsed values of a command's arguments by name
+type Args map[string]interface{}
+
+// Has reports whether an argument was given or has a default
+func (a Args) Has(name string) bool {
+	_, ok := a[name]
+	return ok
+}
+
+// String returns a string, source ID or category argument
+func (a Args) String(name string) string {
+	switch value := a[name].(type) {
+	case string:
+		return value
+	case models.Category:
+		return string(value)
+	}
+	return ""
+}
+
+// Int returns an integer argument
+func (a Args) Int(name string) int {
+	value, _ := a[name].(int)
+	return value
+}
+
+// Bool returns a switch argument
+func (a Args) Bool(name string) bool {
+	value, _ := a[name].(bool)
+	return value
+}
+
+// Category returns a category argument
+func (a Args) Category(name string) models.Category {
+	value, _ := a[name].(models.Category)
+	return value
+}
+
+// Duration returns a duration argument
+func (a Args) Duration(name string) time.Duration {
+	value, _ := a[name].(time.Duration)
+	return value
+}
+
+// Registry is the table of commands, looked up by name or alias
+type Registry struct {
+	commands []*Command
+	byName   map[string]*Command
+}
+
+// NewRegistry creates an empty command registry
+func NewRegistry() *Registry {
+	return &Registry{byName: make(map[string]*Command)}
+}
+
+// Add adds a command to the registry
+func (r *Registry) Add(cmd *Command) {
+	r.commands = append(r.commands, cmd)
+	r.byName[cmd.Name] = cmd
+	for _, alias := range cmd.Aliases {
+		r.byName[alias] = cmd
+	}
+}
+
+// Lookup finds a command by name or alias
+func (r *Registry) Lookup(name string) (*Command, bool) {
+	cmd, ok := r.byName[strings.ToLower(name)]
+	return cmd, ok
+}
+
+// Commands returns the commands in registration order
+func (r *Registry) Commands() []*Command {
+	return r.commands
+}
+
+// arg finds an argument by name or alias
+func (c *Command) arg(name string) *Arg {
+	for _, arg := range c.Args {
+		if strings.EqualFold(arg.Name, name) {
+			return arg
+		}
+		for _, alias := range arg.Aliases {
+			if strings.EqualFold(alias, name) {
+				return arg
+			}
+		}
+	}
+	return nil
+}
+
+// Usage returns the argument synopsis of a command, e.g.
+// "latest [count] [--exploited] [--since <duration>]"
+func (c *Command) Usage() string {
+	parts := []string{c.Name}
+	for _, arg := range c.Args {
+		var part string
+		switch {
+		case arg.Type == ArgAttachment:
+			part = "attached " + arg.Name
+		case arg.Type == ArgBool:
+			part = "--" + arg.Name
+		case arg.Flag:
+			part = fmt.Sprintf("--%s <%s>", arg.Name, arg.Type.placeholder(arg.Name))
+		case arg.Type == ArgText:
+			part = "<" + arg.Name + "...>"
+		case arg.Required:
+			part = "<" + arg.Name + ">"
+		default:
+			part = arg.Name
+		}
+
+		if arg.Required {
+			parts = append(parts, part)
+		} else {
+			parts = append(parts, "["+part+"]")
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// placeholder describes the value of a flag in usage text
+func (t ArgType) placeholder(name string) string {
+	switch t {
+	case ArgInt:
+		return "number"
+	case ArgCategory:
+		return "category"
+	case ArgSource:
+		return "source"
+	case ArgDuration:
+		return "duration"
+	default:
+		return name
+	}
+}
+
+// tokenize splits command text into words, keeping quoted strings together.
+// Double quotes may contain backslash escapes; curly quotes from mobile
+// keyboards count as straight quotes.
+func tokenize(content string) ([]string, error) {
+	content = strings.NewReplacer("“", `"`, "”", `"`, "‘", "'", "’", "'").Replace(content)
+
+	var tokens []string
+	var current strings.Builder
+	inToken := false
+	var quote rune
+	escaped := false
+
+	for _, r := range content {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case quote != 0:
+			if r == '\\' && quote == '"' {
+				escaped = true
+			} else if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || (r == '\'' && !inToken):
+			// Apostrophes inside words are not quotes
+			quote = r
+			inToken = true
+		case unicode.IsSpace(r):
+			if inToken {
+				tokens = append(tokens, current.String())
+				current.Reset()
+				inToken = false
+			}
+		default:
+			current.WriteRune(r)
+			inToken = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("missing closing quote")
+	}
+	if inToken {
+		tokens = append(tokens, current.String())
+	}
+	return tokens, nil
+}
+
+// parseArgs parses prefix command words into a command's arguments
+func (b *Bot) parseArgs(cmd *Command, tokens []string) (Args, error) {
+	raw := make(map[*Arg]string)
+
+	var positional []*Arg
+	for _, arg := range cmd.Args {
+		if !arg.Flag && arg.Type != ArgBool && arg.Type != ArgAttachment {
+			positional = append(positional, arg)
+		}
+	}
+
+	next := 0
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+
+		// --name value, --name=value, or --name for switches
+		if strings.HasPrefix(token, "--") && len(token) > 2 {
+			name, value, hasValue := strings.Cut(token[2:], "=")
+			arg := cmd.arg(name)
+			if arg == nil || arg.Type == ArgAttachment {
+				return nil, fmt.Errorf("unknown option --%s, usage: %s%s", name, b.config.CommandPrefix, cmd.Usage())
+			}
+			if !hasValue {
+				if arg.Type == ArgBool {
+					value = "true"
+				} else if i+1 < len(tokens) {
+					i++
+					value = tokens[i]
+				} else {
+					return nil, fmt.Errorf("option --%s needs a value", arg.Name)
+				}
+			}
+			raw[arg] = value
+			continue
+		}
+
+		// Fill the next positional argument not already given as a flag
+		for next < len(positional) {
+			if _, given := raw[positional[next]]; !given || positional[next].Type == ArgText {
+				break
+			}
+			next++
+		}
+		if next >= len(positional) {
+			return nil, fmt.Errorf("unexpected argument %q, usage: %s%s", token, b.config.CommandPrefix, cmd.Usage())
+		}
+
+		// Text arguments take every remaining word, requoting words that
+		// were quoted so phrases survive
+		if arg := positional[next]; arg.Type == ArgText {
+			if strings.ContainsAny(token, " \t") {
+				token = strconv.Quote(token)
+			}
+			if previous, given := raw[arg]; given {
+				token = previous + " " + token
+			}
+			raw[arg] = token
+			continue
+		}
+
+		raw[positional[next]] = token
+		next++
+	}
+
+	return b.bindArgs(cmd, raw)
+}
+
+// bindArgs converts raw argument values to their declared types, applying
+// defaults and checking required arguments
+func (b *Bot) bindArgs(cmd *Command, raw map[*Arg]string) (Args, error) {
+	args := make(Args)
+
+	for _, arg := range cmd.Args {
+		value, given := raw[arg]
+		if !given || value == "" {
+			if arg.Default == "" {
+				if arg.Required && arg.Type != ArgAttachment {
+					return nil, fmt.Errorf("missing %s, usage: %s%s", arg.Name, b.config.CommandPrefix, cmd.Usage())
+				}
+				continue
+			}
+			value = arg.Default
+		}
+
+		parsed, err := b.parseValue(arg, value)
+		if err != nil {
+			return nil, err
+		}
+		args[arg.Name] = parsed
+	}
+
+	return args, nil
+}
+
+// parseValue converts an argument value to its declared type
+func (b *Bot) parseValue(arg *Arg, value string) (interface{}, error) {
+	switch arg.Type {
+	case ArgInt:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a whole number, not %q", arg.Name, value)
+		}
+		if arg.Max > 0 && (n < arg.Min || n > arg.Max) {
+			return nil, fmt.Errorf("%s must be between %d and %d", arg.Name, arg.Min, arg.Max)
+		}
+		return n, nil
+
+	case ArgBool:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be true or false, not %q", arg.Name, value)
+		}
+		return v, nil
+
+	case ArgCategory:
+		category, ok := parseCategory(value)
+		if !ok {
+			var names []string
+			for _, c := range models.Categories {
+				names = append(names, string(c))
+			}
+			return nil, fmt.Errorf("unknown category %q, expected one of %s", value, strings.Join(names, ", "))
+		}
+		return category, nil
+
+	case ArgSource:
+		source, ok := b.findSource(value)
+		if !ok {
+			return nil, fmt.Errorf("unknown source %q", value)
+		}
+		return source.ID, nil
+
+	case ArgDuration:
+		d, err := parseDuration(value)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("%s must be a duration such as 90m, 24h or 7d, not %q", arg.Name, value)
+		}
+		return d, nil
+
+	default:
+		return value, nil
+	}
+}
+
+// parseDuration parses a Go duration, also accepting whole days and weeks
+// such as 7d or 2w
+func parseDuration(value string) (time.Duration, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+	for suffix, unit := range units {
+		if n, err := strconv.Atoi(strings.TrimSuffix(value, suffix)); err == nil && strings.HasSuffix(value, suffix) {
+			return time.Duration(n) * unit, nil
+		}
+	}
+	return time.ParseDuration(value)
+}
+
+// slashOptions generates the slash command options of a command. Discord
+// requires required options to come first.
+func slashOptions(cmd *Command) []*discordgo.ApplicationCommandOption {
+	options := make([]*discordgo.ApplicationCommandOption, 0, len(cmd.Args))
+	for _, arg := range cmd.Args {
+		option := &discordgo.ApplicationCommandOption{
+			Name:        arg.Name,
+			Description: truncate(arg.Description, 100),
+			Required:    arg.Required,
+		}
+		if option.Description == "" {
+			option.Description = arg.Name
+		}
+
+		switch arg.Type {
+		case ArgInt:
+			option.Type = discordgo.ApplicationCommandOptionInteger
+			if arg.Max > 0 {
+				min := float64(arg.Min)
+				option.MinValue = &min
+				option.MaxValue = float64(arg.Max)
+			}
+		case ArgBool:
+			option.Type = discordgo.ApplicationCommandOptionBoolean
+		case ArgAttachment:
+			option.Type = discordgo.ApplicationCommandOptionAttachment
+		case ArgCategory, ArgSource:
+			option.Type = discordgo.ApplicationCommandOptionString
+			option.Autocomplete = true
+		default:
+			option.Type = discordgo.ApplicationCommandOptionString
+		}
+
+		options = append(options, option)
+	}
+
+	sort.SliceStable(options, func(i, j int) bool {
+		return options[i].Required && !options[j].Required
+	})
+	return options
+}
@@ -0,0 +1,62 @@
+// internal/discord/search.go
+package discord
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/feeds"
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+)
+
+// searchPageSize is the number of results shown per page
+const searchPageSize = defaultListCount
+
+// searchArgs declares the arguments of the search command
+func searchArgs() []*Arg {
+	return []*Arg{
+		{
+			Name:        "query",
+			Type:        ArgText,
+			Required:    true,
+			Description: `Words to find; use "quotes" for phrases and OR for alternatives`,
+		},
+		{
+			Name:        "category",
+			Type:        ArgCategory,
+			Flag:        true,
+			Description: "Only search items in this category",
+		},
+		{
+			Name:        "source",
+			Type:        ArgSource,
+			Flag:        true,
+			Description: "Only search items from this source",
+		},
+		{
+			Name:        "since",
+			Type:        ArgDuration,
+			Flag:        true,
+			Description: "Only search items published within this time, e.g. 7d or 4w",
+		},
+	}
+}
+
+// searchCommand handles the search command
+func (b *Bot) searchCommand(ctx *Context, args Args) error {
+	query := strings.TrimSpace(args.String("query"))
+
+	filter := feeds.IntelFilter{
+		Category: args.Category("category"),
+		SourceID: args.String("source"),
+	}
+	if since := args.Duration("since"); since > 0 {
+		filter.Since = time.Now().UTC().Add(-since)
+	}
+
+	fetch := func(start pageStart, limit int) ([]*models.Intelligence, error) {
+		return b.engine.Search(query, filter, limit, start.offset)
+	}
+	return b.replyPages(ctx, fmt.Sprintf("Search results for %q", query), searchPageSize, fetch)
+}
@@ -0,0 +1,177 @@
+// internal/discord/subscriptions.go
+package discord
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+	"github.com/NullMeDev/Infopulse-Node/internal/subscriptions"
+	"github.com/bwmarrin/discordgo"
+)
+
+// maxSubscriptions limits the subscriptions of each user or channel
+const maxSubscriptions = 25
+
+// channelSubscriptionAction is the permission check and audit entry of
+// managing a channel's subscriptions
+var channelSubscriptionAction = &Command{Name: "subscribe-channel", Permission: PermissionOperator}
+
+// subscribeCommand handles the subscribe command
+func (b *Bot) subscribeCommand(ctx *Context, args Args) error {
+	if ctx.User == nil {
+		return fmt.Errorf("could not identify the user")
+	}
+
+	filter, err := subscriptions.Parse(args.String("filter"))
+	if err != nil {
+		return err
+	}
+	if filter.SourceID != "" {
+		source, ok := b.findSource(filter.SourceID)
+		if !ok {
+			return fmt.Errorf("unknown source %q", filter.SourceID)
+		}
+		filter.SourceID = source.ID
+	}
+
+	sub := &models.Subscription{
+		UserID:  ctx.User.ID,
+		GuildID: ctx.GuildID,
+		Filter:  filter,
+	}
+
+	if value := args.String("channel"); value != "" {
+		channelID, err := b.guildChannel(ctx, value)
+		if err != nil {
+			return err
+		}
+		if !b.allowChannelSubscription(ctx, Args{"channel": channelID, "filter": subscriptions.Format(filter)}) {
+			return fmt.Errorf("you need %s access to subscribe a channel", channelSubscriptionAction.Permission)
+		}
+		sub.ChannelID = channelID
+	}
+
+	count, err := b.engine.CountSubscriptions(sub.UserID, sub.ChannelID)
+	if err != nil {
+		return err
+	}
+	if count >= maxSubscriptions {
+		return fmt.Errorf("each user and channel can have at most %d subscriptions; remove one with %sunsubscribe first",
+			maxSubscriptions, b.config.CommandPrefix)
+	}
+
+	if err := b.engine.AddSubscription(sub); err != nil {
+		return err
+	}
+
+	return ctx.Replyf("Subscription #%d created: `%s`. New matching items will be sent to %s.",
+		sub.ID, subscriptions.Format(sub.Filter), subscriptionTarget(sub))
+}
+
+// unsubscribeCommand handles the unsubscribe command
+func (b *Bot) unsubscribeCommand(ctx *Context, args Args) error {
+	if ctx.User == nil {
+		return fmt.Errorf("could not identify the user")
+	}
+
+	id := int64(args.Int("id"))
+	sub, err := b.engine.GetSubscription(id)
+	if err != nil {
+		return err
+	}
+
+	// Users only see their own DM subscriptions and their guild's channel
+	// subscriptions, so anything else is reported as missing
+	if sub == nil || (sub.ChannelID == "" && sub.UserID != ctx.User.ID) ||
+		(sub.ChannelID != "" && (ctx.GuildID == "" || sub.GuildID != ctx.GuildID)) {
+		return ctx.Replyf("No subscription #%d found. Use %ssubscriptions to list yours.", id, b.config.CommandPrefix)
+	}
+
+	if sub.ChannelID != "" && !b.allowChannelSubscription(ctx, Args{"channel": sub.ChannelID, "unsubscribe": sub.ID}) {
+		return fmt.Errorf("you need %s access to change a channel's subscriptions", channelSubscriptionAction.Permission)
+	}
+
+	if err := b.engine.RemoveSubscription(sub.ID); err != nil {
+		return err
+	}
+	return ctx.Replyf("Removed subscription #%d (`%s`).", sub.ID, subscriptions.Format(sub.Filter))
+}
+
+// subscriptionsCommand handles the subscriptions command
+func (b *Bot) subscriptionsCommand(ctx *Context, args Args) error {
+	if ctx.User == nil {
+		return fmt.Errorf("could not identify the user")
+	}
+
+	subs, err := b.engine.GetSubscriptions(ctx.User.ID, ctx.GuildID)
+	if err != nil {
+		return err
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:     "Subscriptions",
+		Color:     0x00aaff,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Infopulse Node v1.0",
+		},
+	}
+
+	if len(subs) == 0 {
+		embed.Description = fmt.Sprintf("No subscriptions. Add one with `%ssubscribe category:cybersec severity:high`.", b.config.CommandPrefix)
+		return ctx.ReplyEmbed(embed)
+	}
+
+	for _, sub := range subs {
+		if len(embed.Fields) >= maxEmbedFields {
+			embed.Description = fmt.Sprintf("Showing %d of %d subscriptions.", maxEmbedFields, len(subs))
+			break
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  truncate(fmt.Sprintf("#%d to %s", sub.ID, subscriptionTarget(sub)), maxFieldName),
+			Value: truncate(fmt.Sprintf("`%s`\nAdded %s", subscriptions.Format(sub.Filter), discordTimestamp(sub.Created)), maxFieldValue),
+		})
+	}
+	fitEmbed(embed)
+
+	return ctx.ReplyEmbed(embed)
+}
+
+// allowChannelSubscription checks and audits a change to a channel's subscriptions
+func (b *Bot) allowChannelSubscription(ctx *Context, args Args) bool {
+	allowed := b.permissionLevel(ctx) >= channelSubscriptionAction.Permission
+	b.audit(ctx, channelSubscriptionAction, args, allowed)
+	return allowed
+}
+
+// guildChannel resolves a channel mention, ID or "here" to the ID of a
+// channel in the current guild
+func (b *Bot) guildChannel(ctx *Context, value string) (string, error) {
+	if ctx.GuildID == "" {
+		return "", fmt.Errorf("channel subscriptions can only be created in a server")
+	}
+
+	channelID := strings.TrimSuffix(strings.TrimPrefix(value, "<#"), ">")
+	if strings.EqualFold(value, "here") {
+		channelID = ctx.ChannelID
+	}
+
+	channel, err := b.session.State.Channel(channelID)
+	if err != nil {
+		channel, err = b.session.Channel(channelID)
+	}
+	if err != nil || channel.GuildID != ctx.GuildID {
+		return "", fmt.Errorf("unknown channel %q, give a channel mention such as #alerts or \"here\"", value)
+	}
+	return channel.ID, nil
+}
+
+// subscriptionTarget describes where a subscription delivers items
+func subscriptionTarget(sub *models.Subscription) string {
+	if sub.ChannelID != "" {
+		return fmt.Sprintf("<#%s>", sub.ChannelID)
+	}
+	return "you by DM"
+}
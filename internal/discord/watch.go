@@ -0,0 +1,159 @@
+// internal/discord/watch.go
+package discord
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+	"github.com/NullMeDev/Infopulse-Node/internal/watchlist"
+	"github.com/bwmarrin/discordgo"
+)
+
+// maxManifestSize limits the size of uploaded dependency files
+const maxManifestSize = 5 << 20
+
+// watchCommand handles the watch command. With attachments it imports
+// go.sum/package-lock.json files, otherwise it adds a single package.
+func (b *Bot) watchCommand(ctx *Context, args Args) error {
+	if len(ctx.Attachments) > 0 {
+		return b.importManifests(ctx)
+	}
+
+	if args.String("ecosystem") == "" || args.String("package") == "" {
+		return fmt.Errorf("usage: %swatch <ecosystem> <package> [version], or attach a go.sum or package-lock.json", b.config.CommandPrefix)
+	}
+
+	pkg := models.WatchedPackage{
+		Ecosystem: args.String("ecosystem"),
+		Name:      args.String("package"),
+		Version:   args.String("version"),
+		AddedBy:   ctx.Username(),
+	}
+
+	count, err := b.engine.AddWatchedPackages([]models.WatchedPackage{pkg})
+	if err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("Now watching `%s`.", watchlist.Format(pkg))
+	if count == 0 {
+		message = fmt.Sprintf("`%s` is already on the watchlist.", watchlist.Format(pkg))
+	}
+	return ctx.Reply(message)
+}
+
+// importManifests adds the packages from attached dependency files
+func (b *Bot) importManifests(ctx *Context) error {
+	// Downloads can outlast the slash command response window
+	if err := ctx.Defer(); err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	var lines []string
+	for _, attachment := range ctx.Attachments {
+		if attachment.Size > maxManifestSize {
+			lines = append(lines, fmt.Sprintf("`%s`: file too large", attachment.Filename))
+			continue
+		}
+
+		data, err := downloadAttachment(client, attachment.URL)
+		if err != nil {
+			lines = append(lines, fmt.Sprintf("`%s`: %v", attachment.Filename, err))
+			continue
+		}
+
+		packages, err := watchlist.ParseManifest(attachment.Filename, data)
+		if err != nil {
+			lines = append(lines, fmt.Sprintf("`%s`: %v", attachment.Filename, err))
+			continue
+		}
+
+		for i := range packages {
+			packages[i].AddedBy = ctx.Username()
+		}
+
+		count, err := b.engine.AddWatchedPackages(packages)
+		if err != nil {
+			return err
+		}
+
+		b.logger.Info("Bot", fmt.Sprintf("%s imported %d packages from %s", ctx.Username(), count, attachment.Filename))
+		lines = append(lines, fmt.Sprintf("`%s`: %d packages, %d new", attachment.Filename, len(packages), count))
+	}
+
+	return ctx.Reply(strings.Join(lines, "\n"))
+}
+
+// downloadAttachment fetches a Discord attachment
+func downloadAttachment(client *http.Client, url string) ([]byte, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("download failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download failed: HTTP %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, maxManifestSize))
+}
+
+// unwatchCommand handles the unwatch command
+func (b *Bot) unwatchCommand(ctx *Context, args Args) error {
+	ecosystem, name := args.String("ecosystem"), args.String("package")
+	count, err := b.engine.RemoveWatchedPackage(ecosystem, name)
+	if err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("Stopped watching `%s/%s`.", ecosystem, name)
+	if count == 0 {
+		message = fmt.Sprintf("`%s/%s` is not on the watchlist.", ecosystem, name)
+	}
+	return ctx.Reply(message)
+}
+
+// watchlistCommand handles the watchlist command
+func (b *Bot) watchlistCommand(ctx *Context, args Args) error {
+	packages := b.engine.GetWatchlist()
+	if len(packages) == 0 {
+		return ctx.Reply("The watchlist is empty.")
+	}
+
+	// Count packages per ecosystem and list as many as fit
+	counts := make(map[string]int)
+	var names []string
+	for _, pkg := range packages {
+		counts[pkg.Ecosystem]++
+		names = append(names, watchlist.Format(pkg))
+	}
+
+	var summary []string
+	for ecosystem, count := range counts {
+		summary = append(summary, fmt.Sprintf("%s: %d", ecosystem, count))
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("Watchlist (%d packages)", len(packages)),
+		Description: truncate(strings.Join(names, "\n"), maxEmbedDescription),
+		Color:       0x0000ff,
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:  "Ecosystems",
+				Value: strings.Join(summary, ", "),
+			},
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Infopulse Node v1.0",
+		},
+	}
+
+	return ctx.ReplyEmbed(embed)
+}
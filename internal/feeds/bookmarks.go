@@ -0,0 +1,89 @@
+// internal/feeds/bookmarks.go
+package feeds
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+)
+
+// initBookmarks creates the tables of per-user bookmarks and read items
+func (s *Store) initBookmarks() error {
+	_, err := s.db.Exec(`
+	CREATE TABLE IF NOT EXISTS bookmarks (
+		user_id TEXT NOT NULL,
+		intel_id TEXT NOT NULL,
+		created TIMESTAMP NOT NULL,
+		PRIMARY KEY (user_id, intel_id)
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create bookmarks table: %v", err)
+	}
+
+	_, err = s.db.Exec(`
+	CREATE TABLE IF NOT EXISTS read_items (
+		user_id TEXT NOT NULL,
+		intel_id TEXT NOT NULL,
+		read_at TIMESTAMP NOT NULL,
+		PRIMARY KEY (user_id, intel_id)
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create read items table: %v", err)
+	}
+
+	return nil
+}
+
+// ToggleBookmark bookmarks an item for a user, or removes the bookmark if
+// it exists. It reports whether the item is now bookmarked.
+func (s *Store) ToggleBookmark(userID, intelID string) (bool, error) {
+	result, err := s.db.Exec(`DELETE FROM bookmarks WHERE user_id = ? AND intel_id = ?`, userID, intelID)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove bookmark: %v", err)
+	}
+	if removed, err := result.RowsAffected(); err == nil && removed > 0 {
+		return false, nil
+	}
+
+	_, err = s.db.Exec(`
+	INSERT INTO bookmarks (user_id, intel_id, created)
+	VALUES (?, ?, ?)`, userID, intelID, time.Now().UTC())
+	if err != nil {
+		return false, fmt.Errorf("failed to add bookmark: %v", err)
+	}
+	return true, nil
+}
+
+// GetBookmarks retrieves a user's bookmarked items, most recently
+// bookmarked first
+func (s *Store) GetBookmarks(userID string, limit, offset int) ([]*models.Intelligence, error) {
+	rows, err := s.db.Query(`
+	SELECT `+intelligenceColumns+`
+	FROM intelligence
+	JOIN bookmarks b ON b.intel_id = intelligence.id
+	WHERE b.user_id = ?
+	ORDER BY b.created DESC
+	LIMIT ? OFFSET ?`, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bookmarks: %v", err)
+	}
+	items := s.scanIntelligence(rows)
+	rows.Close()
+
+	if err := s.loadRelated(items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// MarkRead records that a user has read an item
+func (s *Store) MarkRead(userID, intelID string) error {
+	_, err := s.db.Exec(`
+	INSERT OR IGNORE INTO read_items (user_id, intel_id, read_at)
+	VALUES (?, ?, ?)`, userID, intelID, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to mark item as read: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+// internal/feeds/digest.go
+package feeds
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/intel"
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+)
+
+// digestCandidates limits how many of a category's latest stories are
+// ranked for a digest
+const digestCandidates = 500
+
+// CountIntelligence counts the stories matching a filter. Alternate
+// reports of a story are not counted.
+func (s *Store) CountIntelligence(filter IntelFilter) (int, error) {
+	conditions, args := filterConditions(filter)
+
+	var count int
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM intelligence WHERE `+strings.Join(conditions, " AND "), args...).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count intelligence: %v", err)
+	}
+	return count, nil
+}
+
+// BuildDigest collects the top stories of each category published within
+// a window ending now. Stories are ranked by severity, then recency; each
+// story appears once, under the first of the categories it belongs to.
+// Categories without stories are left out.
+func (s *Store) BuildDigest(name string, categories []models.Category, window time.Duration, topN int, now time.Time) (*models.Digest, error) {
+	digest := &models.Digest{
+		Name:  name,
+		Since: now.Add(-window),
+		Until: now,
+	}
+
+	shown := make(map[string]bool)
+	for _, category := range categories {
+		filter := IntelFilter{Category: category, Since: digest.Since}
+
+		items, err := s.QueryIntelligence(filter, digestCandidates)
+		if err != nil {
+			return nil, err
+		}
+		if len(items) == 0 {
+			continue
+		}
+
+		total := len(items)
+		if total == digestCandidates {
+			if total, err = s.CountIntelligence(filter); err != nil {
+				return nil, err
+			}
+		}
+
+		// Items come newest first, so a stable sort keeps recency order
+		// within each severity
+		sort.SliceStable(items, func(i, j int) bool {
+			return intel.SeverityRank(items[i].Severity) > intel.SeverityRank(items[j].Severity)
+		})
+
+		section := models.DigestSection{Category: category, Total: total}
+		for _, item := range items {
+			if len(section.Items) >= topN {
+				break
+			}
+			if shown[item.ID] {
+				continue
+			}
+			shown[item.ID] = true
+			section.Items = append(section.Items, item)
+		}
+		if len(section.Items) > 0 {
+			digest.Sections = append(digest.Sections, section)
+		}
+	}
+
+	return digest, nil
+}
@@ -3,23 +3,44 @@ package feeds
 
 import (
 	"fmt"
+	"os"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/NullMeDev/Infopulse-Node/internal/config"
+	"github.com/NullMeDev/Infopulse-Node/internal/intel"
 	"github.com/NullMeDev/Infopulse-Node/internal/logger"
 	"github.com/NullMeDev/Infopulse-Node/internal/models"
+	"github.com/NullMeDev/Infopulse-Node/internal/scheduler"
+	"github.com/NullMeDev/Infopulse-Node/internal/subscriptions"
+	"github.com/NullMeDev/Infopulse-Node/internal/watchlist"
+)
+
+const (
+	// defaultUpdateFreq is used for sources that don't set updateFreq
+	defaultUpdateFreq = 15 * time.Minute
+	// scheduleJitter spreads fetches of sources with equal update frequencies
+	scheduleJitter = 2 * time.Minute
+	// idleWait is how long the update loop sleeps when nothing is scheduled
+	idleWait = time.Minute
+	// minIDPrefix is the shortest ID prefix that items are looked up by
+	minIDPrefix = 4
 )
 
 // Engine manages feed fetching and processing
 type Engine struct {
-	config   *config.Config
-	parser   *Parser
-	store    *Store
-	logger   *logger.Logger
-	sources  []models.FeedSource
-	stopChan chan struct{}
-	wg       sync.WaitGroup
+	config    *config.Config
+	parser    *Parser
+	processor *intel.Processor
+	dedupe    *intel.Deduplicator
+	store     *Store
+	logger    *logger.Logger
+	sources   []models.FeedSource
+	scheduler *scheduler.Scheduler
+	stopChan  chan struct{}
+	newItems  chan struct{}
+	wg        sync.WaitGroup
 }
 
 // NewEngine creates a new feed engine
@@ -33,14 +54,65 @@ func NewEngine(cfg *config.Config, logger *logger.Logger) (*Engine, error) {
 		return nil, fmt.Errorf("failed to create store: %v", err)
 	}
 
+	// Create processing pipeline
+	stages, err := intel.DefaultStages(cfg.FeedSources, cfg.CategoryRules, cfg.CategoryMinConfidence)
+	if err != nil {
+		store.Close()
+		return nil, fmt.Errorf("failed to create processing pipeline: %v", err)
+	}
+
+	// Let story clustering see recently stored items
+	var dedupe *intel.Deduplicator
+	for _, stage := range stages {
+		if deduplicator, ok := stage.(*intel.Deduplicator); ok {
+			dedupe = deduplicator
+			recent, err := store.GetRecentIntelligence(time.Now().UTC().Add(-intel.ClusterWindow))
+			if err != nil {
+				logger.Error("Engine", fmt.Sprintf("Failed to load recent items for deduplication: %v", err))
+				break
+			}
+			deduplicator.Seed(recent)
+		}
+	}
+
 	// Create engine
 	engine := &Engine{
-		config:   cfg,
-		parser:   parser,
-		store:    store,
-		logger:   logger,
-		sources:  cfg.FeedSources,
-		stopChan: make(chan struct{}),
+		config:    cfg,
+		parser:    parser,
+		processor: intel.NewProcessor(logger, stages...),
+		dedupe:    dedupe,
+		store:     store,
+		logger:    logger,
+		sources:   cfg.FeedSources,
+		scheduler: scheduler.New(scheduleJitter),
+		stopChan:  make(chan struct{}),
+		newItems:  make(chan struct{}, 1),
+	}
+
+	// Seed the watchlist from configuration
+	engine.loadWatchlist()
+
+	// Queue new items for outbound sinks. Filters were checked when the
+	// config was loaded.
+	routes := make([]SinkRoute, 0, len(cfg.Sinks))
+	for _, sink := range cfg.Sinks {
+		route := SinkRoute{ID: sink.ID, Categories: sink.Categories}
+		if sink.Filter != "" {
+			if route.Filter, err = subscriptions.Parse(sink.Filter); err != nil {
+				store.Close()
+				return nil, fmt.Errorf("invalid filter of sink %s: %v", sink.ID, err)
+			}
+		}
+		routes = append(routes, route)
+	}
+	store.SetSinkRoutes(routes)
+
+	// Schedule enabled sources at their own update frequency
+	for _, source := range engine.sources {
+		if !source.Enabled {
+			continue
+		}
+		engine.scheduler.Add(source.ID, sourceInterval(source))
 	}
 
 	return engine, nil
@@ -71,45 +143,77 @@ func (e *Engine) Stop() error {
 	return e.store.Close()
 }
 
-// updateLoop periodically updates feeds
+// updateLoop fetches sources as they become due
 func (e *Engine) updateLoop() {
 	defer e.wg.Done()
 
-	// Immediate first update
-	e.updateAllFeeds()
-
-	// Set up ticker for periodic updates
-	ticker := time.NewTicker(15 * time.Minute)
-	defer ticker.Stop()
-
 	for {
+		// Sleep until the next source is due or the schedule changes
+		wait := idleWait
+		if next, ok := e.scheduler.Next(); ok {
+			wait = time.Until(next)
+		}
+		if wait < 0 {
+			wait = 0
+		}
+		timer := time.NewTimer(wait)
+
 		select {
-		case <-ticker.C:
-			e.updateAllFeeds()
+		case <-timer.C:
+			e.dispatchDue()
+		case <-e.scheduler.Wake():
+			timer.Stop()
+			e.dispatchDue()
 		case <-e.stopChan:
+			timer.Stop()
 			e.logger.Info("Engine", "Update loop stopped")
 			return
 		}
 	}
 }
 
-// updateAllFeeds updates all configured feeds
-func (e *Engine) updateAllFeeds() {
-	e.logger.Info("Engine", fmt.Sprintf("Updating %d feeds", len(e.sources)))
+// dispatchDue starts an update for all sources that are currently due
+func (e *Engine) dispatchDue() {
+	ids := e.scheduler.Due(time.Now())
+	if len(ids) == 0 {
+		return
+	}
+
+	var due []models.FeedSource
+	for _, id := range ids {
+		if source, ok := e.sourceByID(id); ok {
+			due = append(due, source)
+		} else {
+			e.scheduler.Remove(id)
+		}
+	}
+
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		e.updateFeeds(due)
+	}()
+}
+
+// updateFeeds fetches the given sources and saves new items
+func (e *Engine) updateFeeds(sources []models.FeedSource) {
+	e.logger.Info("Engine", fmt.Sprintf("Updating %d feeds", len(sources)))
 
 	// Create worker pool
 	type Job struct {
 		source models.FeedSource
 	}
 	type Result struct {
-		source models.FeedSource
-		items  []*models.Intelligence
-		err    error
+		source   models.FeedSource
+		previous models.FetchState
+		state    *models.FetchState
+		items    []*models.Intelligence
+		err      error
 	}
 
-	jobs := make(chan Job, len(e.sources))
-	results := make(chan Result, len(e.sources))
-	
+	jobs := make(chan Job, len(sources))
+	results := make(chan Result, len(sources))
+
 	// Create workers
 	var workersWg sync.WaitGroup
 	workerCount := e.config.MaxConcurrentFetches
@@ -121,24 +225,34 @@ func (e *Engine) updateAllFeeds() {
 		workersWg.Add(1)
 		go func() {
 			defer workersWg.Done()
-			
+
 			for job := range jobs {
-				// Fetch and parse feed
-				items, err := e.parser.ParseFeed(job.source)
+				// Load the state of the previous fetch
+				state, err := e.store.GetFetchState(job.source.ID)
+				if err != nil {
+					e.logger.Warning("Engine", fmt.Sprintf("Failed to load fetch state for %s: %v", job.source.Name, err))
+					state = &models.FetchState{SourceID: job.source.ID}
+				}
+
+				// Fetch and parse feed. The state is saved once the items are
+				// stored, so files processed by this fetch are only recorded
+				// if their items were saved.
+				previous := *state
+				items, err := e.parser.ParseFeed(job.source, state)
+
 				results <- Result{
-					source: job.source,
-					items:  items,
-					err:    err,
+					source:   job.source,
+					previous: previous,
+					state:    state,
+					items:    items,
+					err:      err,
 				}
 			}
 		}()
 	}
 
 	// Queue jobs
-	for _, source := range e.sources {
-		if !source.Enabled {
-			continue
-		}
+	for _, source := range sources {
 		jobs <- Job{source: source}
 	}
 	close(jobs)
@@ -148,44 +262,181 @@ func (e *Engine) updateAllFeeds() {
 	processWg.Add(1)
 	go func() {
 		defer processWg.Done()
-		
+
 		totalItems := 0
 		savedItems := 0
-		
-		for i := 0; i < len(e.sources); i++ {
-			result := <-results
+
+		for result := range results {
+			// Schedule the next fetch whether or not this one succeeded
+			e.scheduler.Done(result.source.ID, time.Now())
+
 			if result.err != nil {
 				e.logger.Error("Engine", fmt.Sprintf("Failed to update feed %s: %v", result.source.Name, result.err))
+				e.saveFetchState(result.source, failedFetchState(result.previous, result.state, result.err))
 				continue
 			}
-			
+
+			// Run items through the processing pipeline
 			totalItems += len(result.items)
-			count, err := e.store.SaveIntelligence(result.items)
+			items := e.processor.Process(result.items)
+
+			count, err := e.store.SaveIntelligence(items)
 			if err != nil {
 				e.logger.Error("Engine", fmt.Sprintf("Failed to save items from %s: %v", result.source.Name, err))
+				e.saveFetchState(result.source, failedFetchState(result.previous, result.state, fmt.Errorf("failed to save items: %v", err)))
 				continue
 			}
-			
+
+			// Attach CVEs from replies to the threads stored by earlier fetches
+			if len(result.state.Mentions) > 0 {
+				if err := e.store.RecordMentions(result.state.Mentions); err != nil {
+					e.logger.Error("Engine", fmt.Sprintf("Failed to record CVE mentions from %s: %v", result.source.Name, err))
+				}
+			}
+
+			// Cluster later items with these only now that they are stored
+			if e.dedupe != nil {
+				e.dedupe.Seed(items)
+			}
+
+			// Record the outcome of this fetch
+			e.saveFetchState(result.source, result.state)
+
 			savedItems += count
 			if count > 0 {
-				e.logger.Info("Engine", fmt.Sprintf("Saved %d/%d new items from %s", count, len(result.items), result.source.Name))
+				e.logger.Info("Engine", fmt.Sprintf("Saved %d/%d new items from %s", count, len(items), result.source.Name))
 			}
 		}
-		
+
 		e.logger.Info("Engine", fmt.Sprintf("Feed update complete. Processed %d items, saved %d new items", totalItems, savedItems))
+
+		// Notify listeners without blocking if a notification is already pending
+		if savedItems > 0 {
+			select {
+			case e.newItems <- struct{}{}:
+			default:
+			}
+		}
 	}()
 
 	// Wait for workers to finish
 	workersWg.Wait()
 	close(results)
-	
+
 	// Wait for processing to finish
 	processWg.Wait()
 }
 
+// saveFetchState records the outcome of a fetch
+func (e *Engine) saveFetchState(source models.FeedSource, state *models.FetchState) {
+	if err := e.store.SaveFetchState(state); err != nil {
+		e.logger.Error("Engine", fmt.Sprintf("Failed to save fetch state for %s: %v", source.Name, err))
+	}
+}
+
+// failedFetchState records a failed fetch in the previous state. Keeping
+// its validators and fetch time means the next fetch requests the same
+// content again instead of getting a 304 or a read window past it.
+func failedFetchState(previous models.FetchState, current *models.FetchState, err error) *models.FetchState {
+	state := previous
+	state.LastStatus = current.LastStatus
+	state.LastBytes = current.LastBytes
+	state.LastError = current.LastError
+	if state.LastError == "" {
+		state.LastError = err.Error()
+	}
+	state.NewFiles = nil
+	state.StaleFiles = nil
+	state.Mentions = nil
+	return &state
+}
+
+// loadWatchlist adds configured packages and dependency files to the watchlist
+func (e *Engine) loadWatchlist() {
+	packages := make([]models.WatchedPackage, 0, len(e.config.Watchlist))
+	for _, pkg := range e.config.Watchlist {
+		pkg.AddedBy = "config"
+		packages = append(packages, pkg)
+	}
+
+	for _, path := range e.config.WatchlistFiles {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			e.logger.Error("Engine", fmt.Sprintf("Failed to read watchlist file %s: %v", path, err))
+			continue
+		}
+
+		parsed, err := watchlist.ParseManifest(path, data)
+		if err != nil {
+			e.logger.Error("Engine", fmt.Sprintf("Failed to parse watchlist file %s: %v", path, err))
+			continue
+		}
+
+		for _, pkg := range parsed {
+			pkg.AddedBy = path
+			packages = append(packages, pkg)
+		}
+	}
+
+	if len(packages) == 0 {
+		return
+	}
+
+	count, err := e.store.AddWatchedPackages(packages)
+	if err != nil {
+		e.logger.Error("Engine", fmt.Sprintf("Failed to load watchlist: %v", err))
+		return
+	}
+	e.logger.Info("Engine", fmt.Sprintf("Loaded %d watched packages (%d new)", len(packages), count))
+}
+
+// sourceByID looks up a configured source
+func (e *Engine) sourceByID(id string) (models.FeedSource, bool) {
+	for _, source := range e.sources {
+		if source.ID == id {
+			return source, true
+		}
+	}
+	return models.FeedSource{}, false
+}
+
+// sourceInterval returns how often a source should be fetched
+func sourceInterval(source models.FeedSource) time.Duration {
+	if source.UpdateFreq <= 0 {
+		return defaultUpdateFreq
+	}
+	return time.Duration(source.UpdateFreq) * time.Minute
+}
+
 // RefreshFeeds forces a refresh of all feeds
 func (e *Engine) RefreshFeeds() {
-	go e.updateAllFeeds()
+	e.scheduler.TriggerAll()
+}
+
+// GetFetchStates returns the last fetch outcome of each source
+func (e *Engine) GetFetchStates() map[string]*models.FetchState {
+	states := make(map[string]*models.FetchState)
+
+	list, err := e.store.GetFetchStates()
+	if err != nil {
+		e.logger.Error("Engine", fmt.Sprintf("Failed to get fetch states: %v", err))
+		return states
+	}
+
+	for _, state := range list {
+		states[state.SourceID] = state
+	}
+	return states
+}
+
+// GetPipelineMetrics returns the metrics of each processing stage
+func (e *Engine) GetPipelineMetrics() []intel.StageMetrics {
+	return e.processor.Metrics()
+}
+
+// GetSchedule returns the fetch schedule for all enabled sources
+func (e *Engine) GetSchedule() []scheduler.Entry {
+	return e.scheduler.Entries()
 }
 
 // GetLatestIntel returns the latest intelligence items
@@ -198,6 +449,21 @@ func (e *Engine) GetLatestIntel(category models.Category, limit int) []*models.I
 	return items
 }
 
+// QueryIntel returns the latest intelligence items matching a filter
+func (e *Engine) QueryIntel(filter IntelFilter, limit int) []*models.Intelligence {
+	items, err := e.store.QueryIntelligence(filter, limit)
+	if err != nil {
+		e.logger.Error("Engine", fmt.Sprintf("Failed to query intelligence: %v", err))
+		return []*models.Intelligence{}
+	}
+	return items
+}
+
+// Search returns stored intelligence items matching a search query
+func (e *Engine) Search(query string, filter IntelFilter, limit, offset int) ([]*models.Intelligence, error) {
+	return e.store.Search(query, filter, limit, offset)
+}
+
 // GetIntelByID gets an intelligence item by ID
 func (e *Engine) GetIntelByID(id string) *models.Intelligence {
 	item, err := e.store.GetIntelligenceByID(id)
@@ -208,6 +474,81 @@ func (e *Engine) GetIntelByID(id string) *models.Intelligence {
 	return item
 }
 
+// FindIntel finds an item by its ID or a unique prefix of it. It returns
+// nil if no item matches.
+func (e *Engine) FindIntel(id string) (*models.Intelligence, error) {
+	item, err := e.store.GetIntelligenceByID(id)
+	if err != nil || item != nil {
+		return item, err
+	}
+
+	if len(id) < minIDPrefix {
+		return nil, nil
+	}
+	ids, err := e.store.FindIntelligenceIDs(id, 5)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(ids) {
+	case 0:
+		return nil, nil
+	case 1:
+		return e.store.GetIntelligenceByID(ids[0])
+	default:
+		return nil, fmt.Errorf("ID %s matches several items (%s), use a longer ID", id, strings.Join(ids, ", "))
+	}
+}
+
+// GetRelatedIntel returns items related to an item by story or CVE
+func (e *Engine) GetRelatedIntel(item *models.Intelligence, limit int) ([]*models.Intelligence, error) {
+	return e.store.GetRelatedIntelligence(item, limit)
+}
+
+// ToggleBookmark bookmarks an item for a user or removes the bookmark
+func (e *Engine) ToggleBookmark(userID, intelID string) (bool, error) {
+	return e.store.ToggleBookmark(userID, intelID)
+}
+
+// GetBookmarks returns a user's bookmarked items
+func (e *Engine) GetBookmarks(userID string, limit, offset int) ([]*models.Intelligence, error) {
+	return e.store.GetBookmarks(userID, limit, offset)
+}
+
+// MarkRead records that a user has read an item
+func (e *Engine) MarkRead(userID, intelID string) error {
+	return e.store.MarkRead(userID, intelID)
+}
+
+// GetPendingSinkDeliveries returns queued sink deliveries that are due,
+// leaving out the sinks in skip
+func (e *Engine) GetPendingSinkDeliveries(limit int, skip map[string]bool) []*models.SinkDelivery {
+	deliveries, err := e.store.GetPendingSinkDeliveries(time.Now().UTC(), limit, skip)
+	if err != nil {
+		e.logger.Error("Engine", fmt.Sprintf("Failed to get pending sink deliveries: %v", err))
+		return nil
+	}
+	return deliveries
+}
+
+// MarkSinkDelivered records that an item has been delivered to a sink
+func (e *Engine) MarkSinkDelivered(sinkID, intelID string) error {
+	return e.store.MarkSinkDelivered(sinkID, intelID)
+}
+
+// MarkSinkFailed records a failed sink delivery, to be retried at retryAt
+// or given up if retryAt is zero
+func (e *Engine) MarkSinkFailed(sinkID, intelID string, retryAt time.Time, reason string) error {
+	return e.store.MarkSinkFailed(sinkID, intelID, retryAt, reason)
+}
+
+// BuildDigest collects the top stories of each category of a digest over
+// its window ending now
+func (e *Engine) BuildDigest(digest config.DigestConfig, now time.Time) (*models.Digest, error) {
+	window := time.Duration(digest.WindowHours) * time.Hour
+	return e.store.BuildDigest(digest.Name, digest.Categories, window, digest.TopN, now)
+}
+
 // GetTotalCount gets the total count of intelligence items
 func (e *Engine) GetTotalCount() int {
 	count, err := e.store.GetTotalCount()
@@ -217,3 +558,113 @@ func (e *Engine) GetTotalCount() int {
 	}
 	return count
 }
+
+// NewItems returns a channel that is signalled after an update saves new items
+func (e *Engine) NewItems() <-chan struct{} {
+	return e.newItems
+}
+
+// GetPendingAutoposts returns items queued for autoposting
+func (e *Engine) GetPendingAutoposts(limit int) []*models.Intelligence {
+	items, err := e.store.GetPendingAutoposts(limit)
+	if err != nil {
+		e.logger.Error("Engine", fmt.Sprintf("Failed to get pending autoposts: %v", err))
+		return []*models.Intelligence{}
+	}
+	return items
+}
+
+// MarkPosted records that an item has been posted to a channel
+func (e *Engine) MarkPosted(id, channelID string) error {
+	return e.store.MarkPosted(id, channelID)
+}
+
+// ExpireAutoposts drops queued items older than maxAge from the autopost queue
+func (e *Engine) ExpireAutoposts(maxAge time.Duration) int {
+	count, err := e.store.ExpireAutoposts(time.Now().UTC().Add(-maxAge))
+	if err != nil {
+		e.logger.Error("Engine", fmt.Sprintf("Failed to expire autoposts: %v", err))
+		return 0
+	}
+	return count
+}
+
+// GetWatchlist returns all watched packages
+func (e *Engine) GetWatchlist() []models.WatchedPackage {
+	packages, err := e.store.GetWatchlist()
+	if err != nil {
+		e.logger.Error("Engine", fmt.Sprintf("Failed to get watchlist: %v", err))
+		return []models.WatchedPackage{}
+	}
+	return packages
+}
+
+// AddWatchedPackages adds packages to the watchlist
+func (e *Engine) AddWatchedPackages(packages []models.WatchedPackage) (int, error) {
+	return e.store.AddWatchedPackages(packages)
+}
+
+// RemoveWatchedPackage removes a package from the watchlist
+func (e *Engine) RemoveWatchedPackage(ecosystem, name string) (int, error) {
+	return e.store.RemoveWatchedPackage(ecosystem, name)
+}
+
+// GetPendingWatchAlerts returns watchlist alerts waiting to be posted
+func (e *Engine) GetPendingWatchAlerts(limit int) []*models.WatchAlert {
+	alerts, err := e.store.GetPendingWatchAlerts(limit)
+	if err != nil {
+		e.logger.Error("Engine", fmt.Sprintf("Failed to get pending watch alerts: %v", err))
+		return []*models.WatchAlert{}
+	}
+	return alerts
+}
+
+// MarkWatchAlertPosted records that a watchlist alert has been posted
+func (e *Engine) MarkWatchAlertPosted(id, channelID string) error {
+	return e.store.MarkWatchAlertPosted(id, channelID)
+}
+
+// AddSubscription stores a new subscription
+func (e *Engine) AddSubscription(sub *models.Subscription) error {
+	return e.store.AddSubscription(sub)
+}
+
+// GetSubscription returns a subscription by ID, or nil if it doesn't exist
+func (e *Engine) GetSubscription(id int64) (*models.Subscription, error) {
+	return e.store.GetSubscription(id)
+}
+
+// GetSubscriptions returns a user's DM subscriptions and a guild's channel subscriptions
+func (e *Engine) GetSubscriptions(userID, guildID string) ([]*models.Subscription, error) {
+	return e.store.GetSubscriptions(userID, guildID)
+}
+
+// CountSubscriptions counts the subscriptions of a channel, or a user's DM subscriptions
+func (e *Engine) CountSubscriptions(userID, channelID string) (int, error) {
+	return e.store.CountSubscriptions(userID, channelID)
+}
+
+// RemoveSubscription deletes a subscription
+func (e *Engine) RemoveSubscription(id int64) error {
+	return e.store.RemoveSubscription(id)
+}
+
+// GetPendingDeliveries returns items queued for subscriptions
+func (e *Engine) GetPendingDeliveries(limit int) []*models.SubscriptionDelivery {
+	deliveries, err := e.store.GetPendingDeliveries(limit)
+	if err != nil {
+		e.logger.Error("Engine", fmt.Sprintf("Failed to get pending deliveries: %v", err))
+		return nil
+	}
+	return deliveries
+}
+
+// MarkDelivered records that an item has been delivered to a subscription
+func (e *Engine) MarkDelivered(subscriptionID int64, intelID string) error {
+	return e.store.MarkDelivered(subscriptionID, intelID)
+}
+
+// MarkDeliveryFailed records a failed delivery, returning true once it is given up
+func (e *Engine) MarkDeliveryFailed(subscriptionID int64, intelID string) (bool, error) {
+	return e.store.MarkDeliveryFailed(subscriptionID, intelID)
+}
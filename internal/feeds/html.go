@@ -0,0 +1,216 @@
+// internal/feeds/html.go
+package feeds
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+	"github.com/PuerkitoBio/goquery"
+)
+
+// htmlDateLayouts are tried in order for dates without a configured layout
+var htmlDateLayouts = []string{
+	time.RFC3339,
+	time.RFC1123Z,
+	time.RFC1123,
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"January 2, 2006",
+	"Jan 2, 2006",
+	"2 January 2006",
+	"02 Jan 2006",
+	"01/02/2006",
+}
+
+// parseHTML scrapes items from a web page using the CSS selectors of the
+// source
+func (p *Parser) parseHTML(source models.FeedSource, state *models.FetchState) ([]*models.Intelligence, error) {
+	selectors := source.HTML
+	if selectors == nil || selectors.Item == "" || selectors.Title == "" {
+		return nil, fmt.Errorf("html source %s needs item and title selectors", source.ID)
+	}
+
+	pageURL, err := url.Parse(source.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid source URL: %v", err)
+	}
+
+	body, err := p.fetch(source.URL, state)
+	if err != nil {
+		return nil, err
+	}
+
+	// Not modified, so there are no new items
+	if body == nil {
+		return nil, nil
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		state.LastError = err.Error()
+		return nil, fmt.Errorf("failed to parse page: %v", err)
+	}
+
+	// Links are relative to the page, or to its base element if it has one
+	base := pageURL
+	if href, ok := doc.Find("base[href]").First().Attr("href"); ok {
+		if resolved, err := pageURL.Parse(href); err == nil {
+			base = resolved
+		}
+	}
+
+	var items []*models.Intelligence
+	now := time.Now().UTC()
+	undated := 0
+
+	doc.Find(selectors.Item).Each(func(_ int, sel *goquery.Selection) {
+		title := collapseSpace(selectValue(sel, selectors.Title))
+		if title == "" {
+			return
+		}
+
+		item := &models.Intelligence{
+			SourceID:  source.ID,
+			Title:     title,
+			URL:       resolveLink(base, itemLink(sel, selectors)),
+			Retrieved: now,
+			Category:  sourceCategory(source, models.CategoryInfosecNews),
+		}
+
+		if selectors.Summary != "" {
+			item.Summary = cleanSummary(collapseSpace(selectValue(sel, selectors.Summary)))
+		}
+
+		item.Published = now
+		if selectors.Date != "" {
+			if published, ok := parseHTMLDate(selectValue(sel, selectors.Date), selectors.DateLayout); ok {
+				item.Published = published
+			} else {
+				undated++
+			}
+		}
+
+		// Generate ID and hash. Items without a link of their own point to
+		// the page and are told apart by title, so their IDs stay the same
+		// across fetches even without dates.
+		if item.URL == "" {
+			item.URL = pageURL.String()
+			item.ID = generateID(&models.Intelligence{URL: item.URL + "#" + item.Title})
+		} else {
+			item.ID = generateID(item)
+		}
+		item.Hash = generateHash(item)
+
+		item.Severity = severityFromTitle(item.Title)
+
+		items = append(items, item)
+	})
+
+	if undated > 0 {
+		p.logger.Warning("Parser", fmt.Sprintf("Could not parse the date of %d items from %s; using the fetch time", undated, source.Name))
+	}
+
+	return items, nil
+}
+
+// selectValue reads the text or attribute named by a "selector@attribute"
+// expression within an item
+func selectValue(item *goquery.Selection, expr string) string {
+	selector, attr, hasAttr := strings.Cut(expr, "@")
+
+	sel := item
+	if selector = strings.TrimSpace(selector); selector != "" {
+		sel = item.Find(selector).First()
+	}
+	if sel.Length() == 0 {
+		return ""
+	}
+
+	if hasAttr {
+		value, _ := sel.Attr(strings.TrimSpace(attr))
+		return value
+	}
+
+	// Machine-readable dates are more reliable than the displayed text
+	if goquery.NodeName(sel) == "time" {
+		if datetime, ok := sel.Attr("datetime"); ok {
+			return datetime
+		}
+	}
+	return sel.Text()
+}
+
+// itemLink finds the link of an item: the configured link, the title's
+// link, or the first link in the item
+func itemLink(item *goquery.Selection, selectors *models.HTMLSelectors) string {
+	if selectors.Link != "" {
+		if strings.Contains(selectors.Link, "@") {
+			return selectValue(item, selectors.Link)
+		}
+		return selectValue(item, selectors.Link+"@href")
+	}
+
+	titleSelector, _, _ := strings.Cut(selectors.Title, "@")
+	if titleSelector = strings.TrimSpace(titleSelector); titleSelector != "" {
+		title := item.Find(titleSelector).First()
+		if href, ok := title.Attr("href"); ok {
+			return href
+		}
+		if href, ok := title.Find("a[href]").First().Attr("href"); ok {
+			return href
+		}
+		if href, ok := title.Closest("a[href]").Attr("href"); ok {
+			return href
+		}
+	}
+
+	if href, ok := item.Attr("href"); ok {
+		return href
+	}
+	href, _ := item.Find("a[href]").First().Attr("href")
+	return href
+}
+
+// resolveLink makes a link absolute. Links that cannot be parsed are
+// returned unchanged.
+func resolveLink(base *url.URL, link string) string {
+	link = strings.TrimSpace(link)
+	if link == "" {
+		return ""
+	}
+	resolved, err := base.Parse(link)
+	if err != nil {
+		return link
+	}
+	return resolved.String()
+}
+
+// parseHTMLDate parses a date with the given layout, or with common
+// layouts if none is given
+func parseHTMLDate(value, layout string) (time.Time, bool) {
+	value = collapseSpace(value)
+	if value == "" {
+		return time.Time{}, false
+	}
+
+	layouts := htmlDateLayouts
+	if layout != "" {
+		layouts = []string{layout}
+	}
+
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// collapseSpace trims text and replaces runs of whitespace with one space
+func collapseSpace(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,332 @@
+// internal/feeds/jsonapi.go
+package feeds
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+)
+
+// jsonDefaultPages bounds the pages requested per fetch of a paginated
+// JSON source that sets no limit of its own
+const jsonDefaultPages = 5
+
+// parseJSON fetches items from a JSON API using the field mapping of the
+// source, following pages up to the mapping's page limit
+func (p *Parser) parseJSON(source models.FeedSource, state *models.FetchState) ([]*models.Intelligence, error) {
+	mapping := source.JSON
+	if mapping == nil || mapping.Title == "" {
+		return nil, fmt.Errorf("json source %s needs a title mapping", source.ID)
+	}
+
+	base, err := url.Parse(source.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid source URL: %v", err)
+	}
+	pageURL := *base
+
+	paginated := mapping.Cursor != "" || mapping.PageParam != ""
+	maxPages := 1
+	if paginated {
+		maxPages = mapping.MaxPages
+		if maxPages <= 0 {
+			maxPages = jsonDefaultPages
+		}
+	}
+
+	var items []*models.Intelligence
+	now := time.Now().UTC()
+	pageNumber := mapping.FirstPage
+	cursors := make(map[string]bool)
+	undated := 0
+
+	for page := 0; page < maxPages; page++ {
+		if mapping.PageParam != "" {
+			query := pageURL.Query()
+			query.Set(mapping.PageParam, strconv.Itoa(pageNumber))
+			pageURL.RawQuery = query.Encode()
+		}
+
+		// Only single-page sources can use conditional requests, since the
+		// validators of one page say nothing about the others
+		body, err := p.fetchURL(pageURL.String(), state, !paginated)
+		if err != nil {
+			return nil, err
+		}
+
+		// Not modified, so there are no new items
+		if body == nil {
+			return nil, nil
+		}
+
+		doc, err := decodeJSON(body)
+		if err != nil {
+			state.LastError = err.Error()
+			return nil, fmt.Errorf("failed to parse JSON response: %v", err)
+		}
+
+		entries, ok := jsonPath(doc, mapping.Items).([]interface{})
+		if !ok {
+			// Later pages may simply have run out of items
+			if page > 0 {
+				break
+			}
+			state.LastError = fmt.Sprintf("no item array at %q", mapping.Items)
+			return nil, fmt.Errorf("no item array at %q in the response of %s", mapping.Items, source.ID)
+		}
+
+		for _, entry := range entries {
+			item, dated := jsonItem(source, mapping, base, entry, now)
+			if item == nil {
+				continue
+			}
+			if !dated {
+				undated++
+			}
+			items = append(items, item)
+		}
+
+		if len(entries) == 0 {
+			break
+		}
+
+		// Move to the next page, stopping when the cursor runs out or
+		// repeats
+		if mapping.Cursor != "" {
+			cursor := strings.TrimSpace(jsonString(jsonPath(doc, mapping.Cursor)))
+			if cursor == "" || cursors[cursor] {
+				break
+			}
+			cursors[cursor] = true
+
+			if mapping.CursorParam != "" {
+				query := pageURL.Query()
+				query.Set(mapping.CursorParam, cursor)
+				pageURL.RawQuery = query.Encode()
+			} else {
+				next, err := pageURL.Parse(cursor)
+				if err != nil {
+					p.logger.Warning("Parser", fmt.Sprintf("Invalid next page URL from %s: %v", source.Name, err))
+					break
+				}
+				pageURL = *next
+			}
+		}
+		pageNumber++
+	}
+
+	if undated > 0 {
+		p.logger.Warning("Parser", fmt.Sprintf("Could not parse the date of %d items from %s; using the fetch time", undated, source.Name))
+	}
+
+	return items, nil
+}
+
+// jsonItem converts an entry of a JSON response into an intelligence item.
+// It returns nil for entries without a title, and false if the entry's
+// date could not be parsed.
+func jsonItem(source models.FeedSource, mapping *models.JSONMapping, base *url.URL, entry interface{}, retrieved time.Time) (*models.Intelligence, bool) {
+	title := collapseSpace(jsonField(entry, mapping.Title))
+	if title == "" {
+		return nil, true
+	}
+
+	item := &models.Intelligence{
+		SourceID:  source.ID,
+		Title:     title,
+		URL:       resolveLink(base, jsonField(entry, mapping.URL)),
+		Retrieved: retrieved,
+		Category:  sourceCategory(source, models.CategoryInfosecNews),
+	}
+
+	if mapping.Summary != "" {
+		item.Summary = cleanSummary(collapseSpace(jsonField(entry, mapping.Summary)))
+	}
+
+	dated := true
+	item.Published = retrieved
+	if mapping.Published != "" {
+		if published, ok := parseJSONDate(jsonField(entry, mapping.Published), mapping.DateLayout); ok {
+			item.Published = published
+		} else {
+			dated = false
+		}
+	}
+
+	// Generate ID and hash. Items without a link of their own point to the
+	// source and are told apart by title, as for html sources.
+	if item.URL == "" {
+		item.URL = base.String()
+		item.ID = generateID(&models.Intelligence{URL: item.URL + "#" + item.Title})
+	} else {
+		item.ID = generateID(item)
+	}
+	item.Hash = generateHash(item)
+
+	item.Severity = normalizeSeverity(jsonField(entry, mapping.Severity))
+	if item.Severity == "" {
+		item.Severity = severityFromTitle(item.Title)
+	}
+
+	return item, dated
+}
+
+// decodeJSON decodes a JSON document, keeping numbers exact so large IDs
+// survive being used in links
+func decodeJSON(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// jsonField evaluates a field mapping against an item: the first of its
+// "|"-separated alternatives with a value, where each is a path or a
+// template with {path} placeholders
+func jsonField(entry interface{}, expr string) string {
+	for _, alternative := range strings.Split(expr, "|") {
+		alternative = strings.TrimSpace(alternative)
+		if alternative == "" {
+			continue
+		}
+
+		var value string
+		if strings.Contains(alternative, "{") {
+			value = expandJSONTemplate(entry, alternative)
+		} else {
+			value = jsonString(jsonPath(entry, alternative))
+		}
+		if strings.TrimSpace(value) != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// expandJSONTemplate replaces each {path} in a template with its value. A
+// template with any empty placeholder expands to nothing, so the next
+// alternative is used instead.
+func expandJSONTemplate(entry interface{}, template string) string {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+
+		value := jsonString(jsonPath(entry, template[start+1:end]))
+		if value == "" {
+			return ""
+		}
+		b.WriteString(template[:start])
+		b.WriteString(value)
+		template = template[end+1:]
+	}
+	b.WriteString(template)
+	return b.String()
+}
+
+// jsonPath looks up a path such as "data.children[0].title" in a decoded
+// document. Negative indexes count from the end of an array. It returns
+// nil if any part of the path is missing.
+func jsonPath(value interface{}, path string) interface{} {
+	path = strings.TrimPrefix(strings.TrimSpace(path), "$")
+
+	for path != "" {
+		switch path[0] {
+		case '.':
+			path = path[1:]
+		case '[':
+			end := strings.IndexByte(path, ']')
+			if end < 0 {
+				return nil
+			}
+			list, ok := value.([]interface{})
+			index, err := strconv.Atoi(strings.TrimSpace(path[1:end]))
+			if !ok || err != nil {
+				return nil
+			}
+			if index < 0 {
+				index += len(list)
+			}
+			if index < 0 || index >= len(list) {
+				return nil
+			}
+			value = list[index]
+			path = path[end+1:]
+		default:
+			end := strings.IndexAny(path, ".[")
+			if end < 0 {
+				end = len(path)
+			}
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			value = object[path[:end]]
+			path = path[end:]
+		}
+	}
+
+	return value
+}
+
+// jsonString formats a scalar JSON value as text. Objects, arrays and null
+// have no text.
+func jsonString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return ""
+	}
+}
+
+// parseJSONDate parses a date with the given layout. Without one, numbers
+// are taken as Unix timestamps in seconds, or milliseconds if they are too
+// large to be seconds, and text is tried against common layouts.
+func parseJSONDate(value, layout string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false
+	}
+
+	switch layout {
+	case "unix", "unixms":
+	case "":
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return parseHTMLDate(value, "")
+		}
+	default:
+		return parseHTMLDate(value, layout)
+	}
+
+	timestamp, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	// Seconds this large would be thousands of years away
+	if layout == "unixms" || (layout == "" && timestamp > 1e11) {
+		return time.UnixMilli(int64(timestamp)).UTC(), true
+	}
+	return time.Unix(int64(timestamp), 0).UTC(), true
+}
@@ -0,0 +1,55 @@
+// internal/feeds/kev_test.go
+package feeds
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+)
+
+func TestKEVFirstFetchBackfillsCatalog(t *testing.T) {
+	parser := newTestParser(t)
+	store := newTestStore(t)
+
+	now := time.Now().UTC()
+	catalog := map[string]interface{}{
+		"title": "CISA Catalog of Known Exploited Vulnerabilities",
+		"vulnerabilities": []map[string]string{
+			{"cveID": "CVE-2021-44228", "vulnerabilityName": "Apache Log4j2 Remote Code Execution Vulnerability", "dateAdded": "2021-12-10"},
+			{"cveID": "CVE-2024-3094", "vulnerabilityName": "XZ Utils Embedded Malicious Code Vulnerability", "dateAdded": now.AddDate(0, 0, -1).Format("2006-01-02")},
+		},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(catalog)
+	}))
+	t.Cleanup(server.Close)
+
+	source := models.FeedSource{
+		ID:          "cisa-kev",
+		Name:        "CISA KEV",
+		URL:         server.URL,
+		Categories:  []models.Category{models.CategoryCybersec},
+		FetchMethod: "cisa-kev",
+	}
+
+	items, err := parser.ParseFeed(source, &models.FetchState{SourceID: source.ID})
+	if err != nil {
+		t.Fatalf("failed to fetch: %v", err)
+	}
+	if count, err := store.SaveIntelligence(items); err != nil || count != 2 {
+		t.Fatalf("saved %d items (%v), want the whole catalog", count, err)
+	}
+
+	// Only the entry added within the lookback is posted
+	queued, err := store.GetPendingAutoposts(10)
+	if err != nil {
+		t.Fatalf("failed to get autoposts: %v", err)
+	}
+	if len(queued) != 1 || queued[0].CVEID != "CVE-2024-3094" {
+		t.Fatalf("queued %d autoposts, want only CVE-2024-3094", len(queued))
+	}
+}
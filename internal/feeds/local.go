@@ -0,0 +1,128 @@
+// internal/feeds/local.go
+package feeds
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+)
+
+// feedFileExtensions are the files read from feed directories. Other files,
+// such as partial transfers, are ignored.
+var feedFileExtensions = map[string]bool{
+	".xml":  true,
+	".rss":  true,
+	".atom": true,
+	".json": true,
+}
+
+// parseLocalFeeds parses RSS, Atom and JSON Feed files from a local file or
+// directory. Each file is processed once: files whose content was already
+// processed are skipped, even if they are renamed or delivered again while
+// the earlier copy is still present. Hashes of files that have been removed
+// are forgotten, so the record doesn't grow without bound.
+func (p *Parser) parseLocalFeeds(source models.FeedSource, path string, state *models.FetchState) ([]*models.Intelligence, error) {
+	files, err := localFeedFiles(path)
+	if err != nil {
+		state.LastError = err.Error()
+		return nil, err
+	}
+	state.LastStatus = 0
+	state.LastError = ""
+
+	var items []*models.Intelligence
+	now := time.Now().UTC()
+	present := make(map[string]bool, len(files))
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			p.logger.Warning("Parser", fmt.Sprintf("Skipping %s: %v", file, err))
+			continue
+		}
+
+		hash := contentHash(data)
+		present[hash] = true
+		if state.ProcessedFiles[hash] {
+			continue
+		}
+		state.LastBytes += int64(len(data))
+
+		// Files that don't parse may still be being written, so they are
+		// not recorded and are tried again on the next fetch
+		feed, err := p.feedParser.Parse(bytes.NewReader(data))
+		if err != nil {
+			p.logger.Warning("Parser", fmt.Sprintf("Skipping %s: %v", file, err))
+			continue
+		}
+
+		items = append(items, feedItems(source, feed, now)...)
+
+		if state.ProcessedFiles == nil {
+			state.ProcessedFiles = make(map[string]bool)
+		}
+		state.ProcessedFiles[hash] = true
+		state.NewFiles = append(state.NewFiles, hash)
+	}
+
+	if len(state.NewFiles) > 0 {
+		p.logger.Info("Parser", fmt.Sprintf("Processed %d new files from %s", len(state.NewFiles), source.Name))
+	}
+
+	// Forget files that were removed since they were processed
+	for hash := range state.ProcessedFiles {
+		if !present[hash] {
+			state.StaleFiles = append(state.StaleFiles, hash)
+		}
+	}
+
+	return items, nil
+}
+
+// localFeedFiles lists the feed files at a path: the file itself, or the
+// feed files in a directory and its subdirectories, skipping hidden ones
+func localFeedFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read feed path: %v", err)
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var files []string
+	err = filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if file != path && strings.HasPrefix(entry.Name(), ".") {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.IsDir() && feedFileExtensions[strings.ToLower(filepath.Ext(file))] {
+			files = append(files, file)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read feed directory: %v", err)
+	}
+
+	return files, nil
+}
+
+// contentHash returns the hex SHA-256 hash of a file's content
+func contentHash(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
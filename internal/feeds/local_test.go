@@ -0,0 +1,74 @@
+// internal/feeds/local_test.go
+package feeds
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+)
+
+const localTestFeed = `<?xml version="1.0"?>
+<rss version="2.0"><channel><title>Drop</title>
+<item><title>Patch released for exploited router flaw</title><link>https://example.com/router</link></item>
+</channel></rss>`
+
+func TestLocalFeedsForgetRemovedFiles(t *testing.T) {
+	parser := newTestParser(t)
+	store := newTestStore(t)
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "drop.xml")
+	source := models.FeedSource{
+		ID:          "drop",
+		Name:        "Drop directory",
+		URL:         dir,
+		Categories:  []models.Category{models.CategoryCybersec},
+		FetchMethod: "rss",
+	}
+
+	// fetch reads the directory with the saved state and saves the new one
+	fetch := func() int {
+		t.Helper()
+		state, err := store.GetFetchState(source.ID)
+		if err != nil {
+			t.Fatalf("failed to load fetch state: %v", err)
+		}
+		items, err := parser.ParseFeed(source, state)
+		if err != nil {
+			t.Fatalf("failed to fetch: %v", err)
+		}
+		if err := store.SaveFetchState(state); err != nil {
+			t.Fatalf("failed to save fetch state: %v", err)
+		}
+		return len(items)
+	}
+	processed := func() int {
+		t.Helper()
+		state, err := store.GetFetchState(source.ID)
+		if err != nil {
+			t.Fatalf("failed to load fetch state: %v", err)
+		}
+		return len(state.ProcessedFiles)
+	}
+
+	if err := os.WriteFile(file, []byte(localTestFeed), 0644); err != nil {
+		t.Fatalf("failed to write feed: %v", err)
+	}
+	if count := fetch(); count != 1 {
+		t.Fatalf("first fetch read %d items, want 1", count)
+	}
+	if count := fetch(); count != 0 {
+		t.Fatalf("repeat fetch read %d items, want the file skipped", count)
+	}
+
+	// Removed files are forgotten
+	if err := os.Remove(file); err != nil {
+		t.Fatalf("failed to remove feed: %v", err)
+	}
+	fetch()
+	if count := processed(); count != 0 {
+		t.Fatalf("%d processed files remembered, want none", count)
+	}
+}
@@ -0,0 +1,511 @@
+// internal/feeds/mbox.go
+package feeds
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+	"github.com/NullMeDev/Infopulse-Node/pkg/utils"
+	"github.com/PuerkitoBio/goquery"
+)
+
+const (
+	// mboxLookback is how old a thread can be and still be reported. Items
+	// are deduplicated by message ID, so re-reading a mailbox only reports
+	// threads that are new.
+	mboxLookback = 7 * 24 * time.Hour
+	// mboxOverlap re-reads messages modified shortly before the previous
+	// fetch, in case the mail fetcher was still delivering them
+	mboxOverlap = time.Hour
+)
+
+var (
+	// messageIDPattern matches the message IDs in In-Reply-To and References
+	messageIDPattern = regexp.MustCompile(`<[^<>\s]+>`)
+	// listTagPattern matches list tags such as "[oss-security]" at the
+	// start of a subject
+	listTagPattern = regexp.MustCompile(`^(\[[^\]]*\]\s*)+`)
+	// attributionPattern matches lines introducing a quote, such as
+	// "On Mon, 1 Jan 2024, Alice wrote:"
+	attributionPattern = regexp.MustCompile(`(?i)\bwrote:$`)
+	// footerPattern matches the rule above mailing list footers
+	footerPattern = regexp.MustCompile(`^_{20,}$`)
+	// mimeWordDecoder decodes RFC 2047 encoded headers
+	mimeWordDecoder = new(mime.WordDecoder)
+)
+
+// mailMessage is a message read from a mailbox
+type mailMessage struct {
+	ID         string
+	Parent     string   // Message ID of the message this one replies to
+	Ancestors  []string // Message IDs of the messages it replies to, root first
+	Subject    string
+	Date       time.Time
+	Body       string
+	ArchiveURL string
+}
+
+// parseMbox reads a local mbox file or Maildir directory and reports each
+// new thread root as an item. Messages are threaded by Message-ID,
+// In-Reply-To and References, and CVE IDs in the subjects of a thread are
+// attached to its root. Replies to threads read by earlier fetches have
+// their CVE IDs recorded against the stored root through state.Mentions.
+func (p *Parser) parseMbox(source models.FeedSource, state *models.FetchState) ([]*models.Intelligence, error) {
+	path, ok := localPath(source.URL)
+	if !ok {
+		return nil, fmt.Errorf("mbox source %s must be a local path", source.ID)
+	}
+
+	messages, err := p.readMailbox(path, state)
+	if err != nil {
+		state.LastError = err.Error()
+		return nil, err
+	}
+	state.LastError = ""
+
+	var items []*models.Intelligence
+	now := time.Now().UTC()
+
+	for _, thread := range threadMessages(messages) {
+		root := thread[0]
+
+		// Replies name the CVEs assigned to a disclosure, but the root may
+		// have been stored by an earlier fetch: the thread started before
+		// this read, or an mbox file was read again. Their CVEs are recorded
+		// against the stored item too.
+		var ids []string
+		switch {
+		case root.Parent != "":
+			// The earliest reply read answers one of these, the root included
+			ids = root.Ancestors
+		case len(thread) > 1 && root.ID != "":
+			ids = []string{root.ID}
+		}
+		if len(ids) > 0 {
+			subjects := make([]string, 0, len(thread))
+			for _, message := range thread {
+				subjects = append(subjects, message.Subject)
+			}
+			if cves := utils.ExtractCVEs(subjects...); len(cves) > 0 {
+				if state.Mentions == nil {
+					state.Mentions = make(map[string][]string)
+				}
+				for _, id := range ids {
+					state.Mentions[threadID(id)] = append(state.Mentions[threadID(id)], cves...)
+				}
+			}
+		}
+
+		// Replies whose thread started before this read belong to an item
+		// that was already reported
+		if root.Parent != "" {
+			continue
+		}
+		if !root.Date.IsZero() && root.Date.Before(now.Add(-mboxLookback)) {
+			continue
+		}
+		items = append(items, mboxItem(source, thread, now))
+	}
+
+	return items, nil
+}
+
+// readMailbox reads the messages of an mbox file or Maildir directory.
+// Mailboxes and Maildir messages not modified since shortly before the
+// previous fetch are skipped.
+func (p *Parser) readMailbox(path string, state *models.FetchState) ([]*mailMessage, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mailbox: %v", err)
+	}
+
+	var since time.Time
+	if !state.LastFetched.IsZero() {
+		since = state.LastFetched.Add(-mboxOverlap)
+	}
+
+	if !info.IsDir() {
+		if !since.IsZero() && !info.ModTime().After(since) {
+			return nil, nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read mailbox: %v", err)
+		}
+		state.LastBytes += int64(len(data))
+
+		var messages []*mailMessage
+		for _, raw := range splitMbox(data) {
+			message, err := parseMailMessage(raw)
+			if err != nil {
+				p.logger.Warning("Parser", fmt.Sprintf("Skipping message in %s: %v", path, err))
+				continue
+			}
+			messages = append(messages, message)
+		}
+		return messages, nil
+	}
+
+	// Delivered messages are in new until a mail client moves them to cur
+	var messages []*mailMessage
+	found := false
+	for _, dir := range []string{"new", "cur"} {
+		entries, err := os.ReadDir(filepath.Join(path, dir))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read Maildir: %v", err)
+		}
+		found = true
+
+		for _, entry := range entries {
+			if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			if !since.IsZero() && !info.ModTime().After(since) {
+				continue
+			}
+
+			file := filepath.Join(path, dir, entry.Name())
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read message: %v", err)
+			}
+			state.LastBytes += int64(len(data))
+
+			message, err := parseMailMessage(data)
+			if err != nil {
+				p.logger.Warning("Parser", fmt.Sprintf("Skipping %s: %v", file, err))
+				continue
+			}
+			messages = append(messages, message)
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("%s is not a Maildir: it has no new or cur directory", path)
+	}
+
+	return messages, nil
+}
+
+// splitMbox splits an mbox file into messages at "From " lines, undoing
+// the ">From " escaping of message lines
+func splitMbox(data []byte) [][]byte {
+	var messages [][]byte
+	var current []byte
+	started := false
+	previousBlank := true
+
+	for _, line := range bytes.SplitAfter(data, []byte("\n")) {
+		if previousBlank && bytes.HasPrefix(line, []byte("From ")) {
+			if started {
+				messages = append(messages, current)
+			}
+			current = nil
+			started = true
+			previousBlank = false
+			continue
+		}
+
+		if started {
+			if unescaped := bytes.TrimLeft(line, ">"); len(unescaped) < len(line) && bytes.HasPrefix(unescaped, []byte("From ")) {
+				line = line[1:]
+			}
+			current = append(current, line...)
+		}
+		previousBlank = len(bytes.TrimSpace(line)) == 0
+	}
+	if started {
+		messages = append(messages, current)
+	}
+
+	return messages
+}
+
+// parseMailMessage parses a message and its plain text body
+func parseMailMessage(data []byte) (*mailMessage, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse message: %v", err)
+	}
+
+	message := &mailMessage{
+		ID:      firstMessageID(msg.Header.Get("Message-ID")),
+		Subject: decodeHeader(msg.Header.Get("Subject")),
+	}
+
+	// A reply names its parent in In-Reply-To, or last in References
+	for _, reference := range messageIDPattern.FindAllString(msg.Header.Get("References"), -1) {
+		message.Ancestors = append(message.Ancestors, strings.Trim(reference, "<>"))
+	}
+	message.Parent = firstMessageID(msg.Header.Get("In-Reply-To"))
+	if message.Parent == "" && len(message.Ancestors) > 0 {
+		message.Parent = message.Ancestors[len(message.Ancestors)-1]
+	}
+	if n := len(message.Ancestors); message.Parent != "" && (n == 0 || message.Ancestors[n-1] != message.Parent) {
+		message.Ancestors = append(message.Ancestors, message.Parent)
+	}
+
+	if date, err := msg.Header.Date(); err == nil {
+		message.Date = date.UTC()
+	}
+
+	// Archived-At (RFC 5064) links to the message in the list archive
+	if archived := strings.Trim(strings.TrimSpace(msg.Header.Get("Archived-At")), "<>"); strings.HasPrefix(archived, "http") {
+		message.ArchiveURL = archived
+	}
+
+	body, err := mailBody(msg.Header.Get("Content-Type"), msg.Header.Get("Content-Transfer-Encoding"), msg.Body)
+	if err != nil {
+		return nil, err
+	}
+	message.Body = stripQuotes(body)
+
+	return message, nil
+}
+
+// mailBody reads the text of a message body, preferring the plain text
+// part of multipart messages
+func mailBody(contentType, encoding string, body io.Reader) (string, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = "text/plain"
+	}
+
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		htmlText := ""
+		for {
+			part, err := reader.NextRawPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return "", fmt.Errorf("failed to read message part: %v", err)
+			}
+
+			partType := part.Header.Get("Content-Type")
+			if partType == "" {
+				partType = "text/plain"
+			}
+			text, err := mailBody(partType, part.Header.Get("Content-Transfer-Encoding"), part)
+			if err != nil {
+				return "", err
+			}
+
+			partMedia, _, _ := mime.ParseMediaType(partType)
+			switch {
+			case partMedia == "text/html":
+				if htmlText == "" {
+					htmlText = text
+				}
+			case text != "":
+				return text, nil
+			}
+		}
+		return htmlText, nil
+	}
+
+	if !strings.HasPrefix(mediaType, "text/") {
+		return "", nil
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read message body: %v", err)
+	}
+	text := decodeCharset(data, params["charset"])
+
+	if mediaType == "text/html" {
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(text))
+		if err != nil {
+			return "", nil
+		}
+		return doc.Text(), nil
+	}
+	return text, nil
+}
+
+// decodeCharset converts Latin-1 text to UTF-8. Other charsets are assumed
+// to be UTF-8 compatible.
+func decodeCharset(data []byte, charset string) string {
+	switch strings.ToLower(charset) {
+	case "iso-8859-1", "latin1", "windows-1252":
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+		}
+		return string(runes)
+	default:
+		return string(data)
+	}
+}
+
+// stripQuotes removes quoted replies, signatures and mailing list footers
+// from a message body
+func stripQuotes(body string) string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		// Signatures and list footers run to the end of the message
+		if strings.TrimRight(line, " ") == "--" || footerPattern.MatchString(trimmed) {
+			break
+		}
+		if strings.HasPrefix(trimmed, ">") {
+			// Drop the line introducing the quote too
+			if n := len(lines); n > 0 && attributionPattern.MatchString(strings.TrimSpace(lines[n-1])) {
+				lines = lines[:n-1]
+			}
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// threadMessages groups messages into threads, each starting with its root
+// and followed by its replies in date order. Replies to messages outside
+// the mailbox belong to older threads; they are grouped under the earliest
+// reply that was read, whose Parent is not empty.
+func threadMessages(messages []*mailMessage) [][]*mailMessage {
+	byID := make(map[string]*mailMessage)
+	for _, message := range messages {
+		if message.ID != "" {
+			byID[message.ID] = message
+		}
+	}
+
+	threads := make(map[*mailMessage][]*mailMessage)
+	var roots []*mailMessage
+	for _, message := range messages {
+		root := message
+		seen := map[*mailMessage]bool{root: true}
+		for root != nil && root.Parent != "" {
+			parent := byID[root.Parent]
+			if parent == nil {
+				break
+			}
+			if seen[parent] {
+				root = nil
+				break
+			}
+			seen[parent] = true
+			root = parent
+		}
+		if root == nil {
+			continue
+		}
+
+		if root == message {
+			roots = append(roots, root)
+		} else {
+			threads[root] = append(threads[root], message)
+		}
+	}
+
+	result := make([][]*mailMessage, 0, len(roots))
+	for _, root := range roots {
+		replies := threads[root]
+		sort.SliceStable(replies, func(i, j int) bool {
+			return replies[i].Date.Before(replies[j].Date)
+		})
+		result = append(result, append([]*mailMessage{root}, replies...))
+	}
+	return result
+}
+
+// mboxItem converts a thread into an intelligence item for its root
+func mboxItem(source models.FeedSource, thread []*mailMessage, retrieved time.Time) *models.Intelligence {
+	root := thread[0]
+
+	title := strings.TrimSpace(listTagPattern.ReplaceAllString(collapseSpace(root.Subject), ""))
+	if title == "" {
+		title = "(no subject)"
+	}
+
+	item := &models.Intelligence{
+		SourceID:  source.ID,
+		Category:  sourceCategory(source, models.CategoryCybersec),
+		Title:     title,
+		URL:       root.ArchiveURL,
+		Summary:   cleanSummary(collapseSpace(root.Body)),
+		Published: root.Date,
+		Retrieved: retrieved,
+	}
+	if item.Published.IsZero() {
+		item.Published = retrieved
+	}
+
+	// Replies often announce the CVE assigned to a disclosure
+	subjects := make([]string, 0, len(thread))
+	for _, message := range thread {
+		subjects = append(subjects, message.Subject)
+	}
+	if cves := utils.ExtractCVEs(subjects...); len(cves) > 0 {
+		item.CVEID = cves[0]
+		if len(cves) > 1 {
+			item.Aliases = cves[1:]
+		}
+	}
+
+	// The message ID identifies the thread even without an archive link
+	if root.ID != "" {
+		item.ID = threadID(root.ID)
+	} else {
+		item.ID = generateID(item)
+	}
+	item.Hash = generateHash(item)
+
+	item.Severity = severityFromTitle(item.Title)
+	return item
+}
+
+// threadID returns the ID of the item for a thread with the given root
+// message ID
+func threadID(messageID string) string {
+	return generateID(&models.Intelligence{URL: "mid:" + messageID})
+}
+
+// firstMessageID returns the first message ID in a header, without its
+// angle brackets
+func firstMessageID(header string) string {
+	if id := messageIDPattern.FindString(header); id != "" {
+		return strings.Trim(id, "<>")
+	}
+	return strings.Trim(strings.TrimSpace(header), "<>")
+}
+
+// decodeHeader decodes RFC 2047 encoded words in a header
+func decodeHeader(value string) string {
+	if decoded, err := mimeWordDecoder.DecodeHeader(value); err == nil {
+		return decoded
+	}
+	return value
+}
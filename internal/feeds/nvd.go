@@ -0,0 +1,281 @@
+// internal/feeds/nvd.go
+package feeds
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+)
+
+const (
+	// nvdInitialWindow is how far back the first fetch of an NVD source looks
+	nvdInitialWindow = 7 * 24 * time.Hour
+	// nvdOverlap re-requests a margin before the previous fetch so late updates aren't missed
+	nvdOverlap = time.Hour
+	// nvdMaxWindow is the longest date range the NVD API accepts
+	nvdMaxWindow = 120 * 24 * time.Hour
+	// nvdMaxPages bounds the number of pages requested per fetch
+	nvdMaxPages = 10
+	// nvdTimeLayout is the timestamp format used by the NVD API
+	nvdTimeLayout = "2006-01-02T15:04:05.000"
+	// nvdDetailURL is the public page for a CVE
+	nvdDetailURL = "https://nvd.nist.gov/vuln/detail/"
+)
+
+// nvdResponse is a page of the NVD CVE API 2.0
+type nvdResponse struct {
+	ResultsPerPage  int `json:"resultsPerPage"`
+	StartIndex      int `json:"startIndex"`
+	TotalResults    int `json:"totalResults"`
+	Vulnerabilities []struct {
+		CVE nvdCVE `json:"cve"`
+	} `json:"vulnerabilities"`
+}
+
+// nvdCVE is a single CVE record
+type nvdCVE struct {
+	ID           string `json:"id"`
+	Published    string `json:"published"`
+	LastModified string `json:"lastModified"`
+	VulnStatus   string `json:"vulnStatus"`
+	Descriptions []struct {
+		Lang  string `json:"lang"`
+		Value string `json:"value"`
+	} `json:"descriptions"`
+	Metrics struct {
+		CVSSMetricV40 []nvdMetric `json:"cvssMetricV40"`
+		CVSSMetricV31 []nvdMetric `json:"cvssMetricV31"`
+		CVSSMetricV30 []nvdMetric `json:"cvssMetricV30"`
+		CVSSMetricV2  []nvdMetric `json:"cvssMetricV2"`
+	} `json:"metrics"`
+	Configurations []struct {
+		Nodes []struct {
+			CPEMatch []struct {
+				Vulnerable bool   `json:"vulnerable"`
+				Criteria   string `json:"criteria"`
+			} `json:"cpeMatch"`
+		} `json:"nodes"`
+	} `json:"configurations"`
+}
+
+// nvdMetric is a CVSS metric of any version
+type nvdMetric struct {
+	Source   string `json:"source"`
+	Type     string `json:"type"`
+	CVSSData struct {
+		Version      string  `json:"version"`
+		VectorString string  `json:"vectorString"`
+		BaseScore    float64 `json:"baseScore"`
+		BaseSeverity string  `json:"baseSeverity"`
+	} `json:"cvssData"`
+	// CVSS v2 reports severity outside cvssData
+	BaseSeverity string `json:"baseSeverity"`
+}
+
+// parseNVD fetches CVEs from an NVD CVE API 2.0 endpoint. Each fetch asks
+// for CVEs modified since the previous successful fetch, following pages
+// up to nvdMaxPages. A window with more results than that is narrowed, and
+// the next fetch resumes where it ends.
+func (p *Parser) parseNVD(source models.FeedSource, state *models.FetchState) ([]*models.Intelligence, error) {
+	base, err := url.Parse(source.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid NVD URL: %v", err)
+	}
+
+	// Restrict to recently modified CVEs unless the source sets its own window
+	query := base.Query()
+	windowed := query.Get("lastModStartDate") == "" && query.Get("pubStartDate") == ""
+	var start, end time.Time
+	if windowed {
+		end = time.Now().UTC()
+		start = end.Add(-nvdInitialWindow)
+		if !state.LastFetched.IsZero() {
+			start = state.LastFetched.Add(-nvdOverlap)
+		}
+		if start.Before(end.Add(-nvdMaxWindow)) {
+			start = end.Add(-nvdMaxWindow)
+		}
+		query.Set("lastModStartDate", start.Format(nvdTimeLayout)+"Z")
+		query.Set("lastModEndDate", end.Format(nvdTimeLayout)+"Z")
+	}
+
+	resp, err := p.fetchNVDPage(base, query, 0, state)
+	if err != nil {
+		return nil, err
+	}
+
+	// Halve the window until its results fit in nvdMaxPages, leaving the
+	// rest of it to the next fetch
+	for windowed && resp.ResultsPerPage > 0 && resp.TotalResults > nvdMaxPages*resp.ResultsPerPage && end.Sub(start) > 2*nvdOverlap {
+		end = start.Add(end.Sub(start) / 2)
+		query.Set("lastModEndDate", end.Format(nvdTimeLayout)+"Z")
+		state.Resume = end
+
+		resp, err = p.fetchNVDPage(base, query, 0, state)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !state.Resume.IsZero() {
+		p.logger.Info("Parser", fmt.Sprintf("NVD window of %s has %d results, reading up to %s", source.Name, resp.TotalResults, end.Format(time.RFC3339)))
+	}
+
+	var items []*models.Intelligence
+	now := time.Now().UTC()
+
+	for page := 1; ; page++ {
+		for _, vuln := range resp.Vulnerabilities {
+			items = append(items, nvdItem(source, vuln.CVE, now))
+		}
+
+		// Stop once all results have been read
+		startIndex := resp.StartIndex + len(resp.Vulnerabilities)
+		if len(resp.Vulnerabilities) == 0 || startIndex >= resp.TotalResults {
+			break
+		}
+		if page == nvdMaxPages {
+			p.logger.Warning("Parser", fmt.Sprintf("NVD source %s has more than %d pages, skipping %d results", source.Name, nvdMaxPages, resp.TotalResults-startIndex))
+			break
+		}
+
+		resp, err = p.fetchNVDPage(base, query, startIndex, state)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return items, nil
+}
+
+// fetchNVDPage requests a page of results starting at startIndex
+func (p *Parser) fetchNVDPage(base *url.URL, query url.Values, startIndex int, state *models.FetchState) (*nvdResponse, error) {
+	query.Set("startIndex", strconv.Itoa(startIndex))
+	base.RawQuery = query.Encode()
+
+	body, err := p.fetchURL(base.String(), state, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp nvdResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		state.LastError = err.Error()
+		return nil, fmt.Errorf("failed to parse NVD response: %v", err)
+	}
+	return &resp, nil
+}
+
+// nvdItem converts a CVE record into an intelligence item
+func nvdItem(source models.FeedSource, cve nvdCVE, retrieved time.Time) *models.Intelligence {
+	description := ""
+	for _, desc := range cve.Descriptions {
+		if desc.Lang == "en" {
+			description = desc.Value
+			break
+		}
+	}
+
+	title := cve.ID
+	if description != "" {
+		title = fmt.Sprintf("%s: %s", cve.ID, firstSentence(description, 120))
+	}
+
+	item := &models.Intelligence{
+		SourceID:  source.ID,
+		Category:  sourceCategory(source, models.CategoryCybersec),
+		Title:     title,
+		URL:       nvdDetailURL + cve.ID,
+		Summary:   cleanSummary(description),
+		Published: parseNVDTime(cve.Published, retrieved),
+		Retrieved: retrieved,
+		Modified:  parseNVDTime(cve.LastModified, time.Time{}),
+		CVEID:     cve.ID,
+	}
+
+	// Use the most specific CVSS metric available
+	if metric := selectNVDMetric(cve); metric != nil {
+		item.CVSSScore = metric.CVSSData.BaseScore
+		item.CVSSVector = metric.CVSSData.VectorString
+		item.Severity = strings.ToUpper(metric.CVSSData.BaseSeverity)
+		if item.Severity == "" {
+			item.Severity = strings.ToUpper(metric.BaseSeverity)
+		}
+	}
+
+	// Collect vulnerable platforms
+	seen := make(map[string]bool)
+	for _, config := range cve.Configurations {
+		for _, node := range config.Nodes {
+			for _, match := range node.CPEMatch {
+				if !match.Vulnerable || seen[match.Criteria] {
+					continue
+				}
+				seen[match.Criteria] = true
+				item.CPEs = append(item.CPEs, match.Criteria)
+			}
+		}
+	}
+
+	item.ID = generateID(item)
+	item.Hash = generateHash(item)
+	return item
+}
+
+// selectNVDMetric picks the CVSS metric to report, preferring v3.1, then
+// v3.0, v4.0 and v2, and the primary (NVD) score within a version
+func selectNVDMetric(cve nvdCVE) *nvdMetric {
+	for _, metrics := range [][]nvdMetric{
+		cve.Metrics.CVSSMetricV31,
+		cve.Metrics.CVSSMetricV30,
+		cve.Metrics.CVSSMetricV40,
+		cve.Metrics.CVSSMetricV2,
+	} {
+		if len(metrics) == 0 {
+			continue
+		}
+		for i := range metrics {
+			if metrics[i].Type == "Primary" {
+				return &metrics[i]
+			}
+		}
+		return &metrics[0]
+	}
+	return nil
+}
+
+// parseNVDTime parses an NVD timestamp, which is UTC without a zone suffix
+func parseNVDTime(value string, fallback time.Time) time.Time {
+	for _, layout := range []string{nvdTimeLayout, "2006-01-02T15:04:05", time.RFC3339} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC()
+		}
+	}
+	return fallback
+}
+
+// firstSentence returns the first sentence of text, truncated to max characters
+func firstSentence(text string, max int) string {
+	if i := strings.Index(text, ". "); i > 0 {
+		text = text[:i]
+	}
+	text = strings.TrimSuffix(strings.TrimSpace(text), ".")
+
+	runes := []rune(text)
+	if len(runes) > max {
+		return string(runes[:max-3]) + "..."
+	}
+	return text
+}
+
+// sourceCategory returns the primary category of a source, or fallback if it has none
+func sourceCategory(source models.FeedSource, fallback models.Category) models.Category {
+	if len(source.Categories) > 0 {
+		return source.Categories[0]
+	}
+	return fallback
+}
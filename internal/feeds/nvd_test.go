@@ -0,0 +1,288 @@
+// internal/feeds/nvd_test.go
+package feeds
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/logger"
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+	"github.com/NullMeDev/Infopulse-Node/internal/subscriptions"
+)
+
+// newTestParser creates a parser that logs to stdout
+func newTestParser(t *testing.T) *Parser {
+	t.Helper()
+	log, err := logger.NewLogger("")
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	return NewParser(5, log)
+}
+
+// newTestStore creates a store in a temporary directory
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	log, err := logger.NewLogger("")
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	store, err := NewStore(filepath.Join(t.TempDir(), "intel.db"), log)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+// serveNVD serves a recorded NVD API response from testdata, switching to
+// another fixture whenever the returned function is called
+func serveNVD(t *testing.T, name string) (*httptest.Server, func(string)) {
+	t.Helper()
+	var mu sync.Mutex
+	current := name
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("lastModStartDate") == "" {
+			http.Error(w, "missing lastModStartDate", http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		data, err := os.ReadFile(filepath.Join("testdata", current))
+		mu.Unlock()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+	return server, func(name string) {
+		mu.Lock()
+		current = name
+		mu.Unlock()
+	}
+}
+
+// fetchNVD fetches and saves a single CVE from an NVD source and returns
+// the stored item
+func fetchNVD(t *testing.T, parser *Parser, store *Store, source models.FeedSource) *models.Intelligence {
+	t.Helper()
+	state := &models.FetchState{SourceID: source.ID}
+	items, err := parser.ParseFeed(source, state)
+	if err != nil {
+		t.Fatalf("failed to fetch: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("got %d items, want 1", len(items))
+	}
+	if _, err := store.SaveIntelligence(items); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+	stored, err := store.GetIntelligenceByID(items[0].ID)
+	if err != nil || stored == nil {
+		t.Fatalf("failed to load stored item: %v", err)
+	}
+	return stored
+}
+
+// nvdSource is an NVD source fetching from a test server
+func nvdSource(server *httptest.Server) models.FeedSource {
+	return models.FeedSource{
+		ID:          "nvd",
+		Name:        "NVD",
+		URL:         server.URL + "/rest/json/cves/2.0",
+		Categories:  []models.Category{models.CategoryCybersec},
+		FetchMethod: "nvd",
+	}
+}
+
+func TestNVDUpdatesRevisedCVEs(t *testing.T) {
+	parser := newTestParser(t)
+	store := newTestStore(t)
+	server, replay := serveNVD(t, "nvd_received.json")
+
+	source := nvdSource(server)
+	fetch := func() *models.Intelligence {
+		t.Helper()
+		return fetchNVD(t, parser, store, source)
+	}
+
+	// Newly published CVEs have no analysis yet
+	item := fetch()
+	if item.CVEID != "CVE-2024-3094" {
+		t.Errorf("CVE ID = %q, want CVE-2024-3094", item.CVEID)
+	}
+	if item.CVSSScore != 0 || len(item.CPEs) != 0 {
+		t.Errorf("received CVE has CVSS %v and CPEs %v, want none", item.CVSSScore, item.CPEs)
+	}
+
+	// The analysis arrives in a later revision of the same CVE
+	replay("nvd_analyzed.json")
+	item = fetch()
+	if item.CVSSScore != 10 || item.Severity != "CRITICAL" {
+		t.Errorf("analyzed CVE has CVSS %v %q, want 10 CRITICAL", item.CVSSScore, item.Severity)
+	}
+	if item.CVSSVector != "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H" {
+		t.Errorf("CVSS vector = %q", item.CVSSVector)
+	}
+	if len(item.CPEs) != 2 {
+		t.Errorf("CPEs = %v, want 2", item.CPEs)
+	}
+
+	// An older revision, e.g. from an overlapping window, changes nothing
+	replay("nvd_received.json")
+	item = fetch()
+	if item.CVSSScore != 10 || len(item.CPEs) != 2 {
+		t.Errorf("older revision replaced the analysis: CVSS %v, CPEs %v", item.CVSSScore, item.CPEs)
+	}
+}
+
+func TestNVDRevisionsReachSubscriptions(t *testing.T) {
+	parser := newTestParser(t)
+	store := newTestStore(t)
+	server, replay := serveNVD(t, "nvd_received.json")
+	source := nvdSource(server)
+
+	filter, err := subscriptions.Parse("severity:high")
+	if err != nil {
+		t.Fatalf("failed to parse filter: %v", err)
+	}
+	sub := &models.Subscription{UserID: "user", Filter: filter, Created: time.Now()}
+	if err := store.AddSubscription(sub); err != nil {
+		t.Fatalf("failed to add subscription: %v", err)
+	}
+
+	pending := func() []*models.SubscriptionDelivery {
+		t.Helper()
+		deliveries, err := store.GetPendingDeliveries(10)
+		if err != nil {
+			t.Fatalf("failed to get deliveries: %v", err)
+		}
+		return deliveries
+	}
+
+	// Without a severity the received CVE matches no severity filter
+	fetchNVD(t, parser, store, source)
+	if deliveries := pending(); len(deliveries) != 0 {
+		t.Fatalf("got %d deliveries for the received CVE, want none", len(deliveries))
+	}
+
+	// The analyzed revision is critical
+	replay("nvd_analyzed.json")
+	item := fetchNVD(t, parser, store, source)
+	deliveries := pending()
+	if len(deliveries) != 1 || deliveries[0].Item.ID != item.ID {
+		t.Fatalf("got %d deliveries for the analyzed CVE, want 1", len(deliveries))
+	}
+
+	// A delivered story isn't queued again by later revisions
+	if err := store.MarkDelivered(sub.ID, item.ID); err != nil {
+		t.Fatalf("failed to mark delivered: %v", err)
+	}
+	replay("nvd_analyzed_again.json")
+	fetchNVD(t, parser, store, source)
+	if deliveries := pending(); len(deliveries) != 0 {
+		t.Fatalf("got %d deliveries after another revision, want none", len(deliveries))
+	}
+}
+
+func TestNVDRevisionsReachFilteredSinks(t *testing.T) {
+	parser := newTestParser(t)
+	store := newTestStore(t)
+	server, replay := serveNVD(t, "nvd_received.json")
+	source := nvdSource(server)
+
+	filter, err := subscriptions.Parse("severity:critical")
+	if err != nil {
+		t.Fatalf("failed to parse filter: %v", err)
+	}
+	store.SetSinkRoutes([]SinkRoute{
+		{ID: "all"},
+		{ID: "critical", Filter: filter},
+	})
+
+	pending := func() map[string]int {
+		t.Helper()
+		deliveries, err := store.GetPendingSinkDeliveries(time.Now().Add(time.Minute), 10, nil)
+		if err != nil {
+			t.Fatalf("failed to get sink deliveries: %v", err)
+		}
+		sinks := make(map[string]int)
+		for _, delivery := range deliveries {
+			sinks[delivery.SinkID]++
+		}
+		return sinks
+	}
+
+	fetchNVD(t, parser, store, source)
+	if sinks := pending(); sinks["all"] != 1 || sinks["critical"] != 0 {
+		t.Fatalf("received CVE queued for sinks %v, want only all", sinks)
+	}
+
+	// The analyzed revision matches the filtered route, and the unfiltered
+	// route isn't queued again
+	replay("nvd_analyzed.json")
+	fetchNVD(t, parser, store, source)
+	if sinks := pending(); sinks["all"] != 1 || sinks["critical"] != 1 {
+		t.Fatalf("analyzed CVE queued for sinks %v, want all and critical once", sinks)
+	}
+}
+
+func TestNVDResumesLargeWindows(t *testing.T) {
+	parser := newTestParser(t)
+	received, err := os.ReadFile(filepath.Join("testdata", "nvd_received.json"))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	// Windows longer than two days have more results than fit in the page
+	// limit, and the server lists only their first page
+	var windows [][2]time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		start, err1 := time.Parse(nvdTimeLayout+"Z", query.Get("lastModStartDate"))
+		end, err2 := time.Parse(nvdTimeLayout+"Z", query.Get("lastModEndDate"))
+		if err1 != nil || err2 != nil {
+			http.Error(w, "invalid window", http.StatusBadRequest)
+			return
+		}
+		windows = append(windows, [2]time.Time{start, end})
+
+		data := received
+		if end.Sub(start) > 48*time.Hour {
+			data = bytes.Replace(data, []byte(`"totalResults": 1`), []byte(`"totalResults": 100`), 1)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+
+	state := &models.FetchState{SourceID: "nvd"}
+	items, err := parser.ParseFeed(nvdSource(server), state)
+	if err != nil {
+		t.Fatalf("failed to fetch: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("got %d items, want 1", len(items))
+	}
+
+	// The seven day window is halved twice, and the next fetch resumes at
+	// the end of the window that was read
+	if len(windows) != 3 {
+		t.Fatalf("requested %d windows, want 3", len(windows))
+	}
+	read := windows[len(windows)-1]
+	if read[1].Sub(read[0]) > 48*time.Hour {
+		t.Errorf("read a window of %s, want at most two days", read[1].Sub(read[0]))
+	}
+	if diff := state.LastFetched.Sub(read[1]); diff < 0 || diff >= time.Millisecond {
+		t.Errorf("next fetch resumes at %s, want %s", state.LastFetched, read[1])
+	}
+}
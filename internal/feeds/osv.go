@@ -0,0 +1,369 @@
+// internal/feeds/osv.go
+package feeds
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+)
+
+// osvDetailURL is the public page for an OSV advisory
+const osvDetailURL = "https://osv.dev/vulnerability/"
+
+// osvAdvisory is an advisory in the OSV schema
+type osvAdvisory struct {
+	ID        string   `json:"id"`
+	Modified  string   `json:"modified"`
+	Published string   `json:"published"`
+	Withdrawn string   `json:"withdrawn"`
+	Aliases   []string `json:"aliases"`
+	Summary   string   `json:"summary"`
+	Details   string   `json:"details"`
+	Severity  []struct {
+		Type  string `json:"type"`
+		Score string `json:"score"`
+	} `json:"severity"`
+	Affected []struct {
+		Package struct {
+			Ecosystem string `json:"ecosystem"`
+			Name      string `json:"name"`
+		} `json:"package"`
+		Ranges []struct {
+			Type   string     `json:"type"`
+			Events []osvEvent `json:"events"`
+		} `json:"ranges"`
+		Versions         []string         `json:"versions"`
+		DatabaseSpecific osvDatabaseExtra `json:"database_specific"`
+	} `json:"affected"`
+	DatabaseSpecific osvDatabaseExtra `json:"database_specific"`
+}
+
+// osvEvent is a version event within an affected range
+type osvEvent struct {
+	Introduced   string `json:"introduced"`
+	Fixed        string `json:"fixed"`
+	LastAffected string `json:"last_affected"`
+}
+
+// osvDatabaseExtra holds the database-specific fields we use
+type osvDatabaseExtra struct {
+	Severity string `json:"severity"`
+}
+
+// parseOSV reads OSV advisories from a URL or a local path. URLs may serve a
+// single advisory, an array of advisories or an OSV API response; local
+// paths may be a JSON file, a zip dump or a directory of JSON files. On the
+// first fetch, advisories published before the lookback are backfilled.
+func (p *Parser) parseOSV(source models.FeedSource, state *models.FetchState) ([]*models.Intelligence, error) {
+	var advisories []osvAdvisory
+
+	if path, ok := localPath(source.URL); ok {
+		parsed, err := p.readOSVPath(path, state)
+		if err != nil {
+			state.LastError = err.Error()
+			return nil, err
+		}
+		advisories = parsed
+	} else {
+		body, err := p.fetch(source.URL, state)
+		if err != nil {
+			return nil, err
+		}
+
+		// Not modified, so there are no new advisories
+		if body == nil {
+			return nil, nil
+		}
+
+		parsed, err := decodeOSV(body)
+		if err != nil {
+			state.LastError = err.Error()
+			return nil, err
+		}
+		advisories = parsed
+	}
+
+	var items []*models.Intelligence
+	now := time.Now().UTC()
+
+	for _, advisory := range advisories {
+		if advisory.ID == "" || advisory.Withdrawn != "" {
+			continue
+		}
+		items = append(items, osvItem(source, advisory, now))
+	}
+
+	markBackfill(items, state)
+	return items, nil
+}
+
+// readOSVPath reads advisories from a local file, zip or directory. Files
+// not modified since the previous fetch are skipped.
+func (p *Parser) readOSVPath(path string, state *models.FetchState) ([]osvAdvisory, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OSV path: %v", err)
+	}
+
+	since := state.LastFetched
+	if !info.IsDir() {
+		if !since.IsZero() && !info.ModTime().After(since) {
+			return nil, nil
+		}
+		if strings.EqualFold(filepath.Ext(path), ".zip") {
+			return p.readOSVZip(path, state)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read OSV file: %v", err)
+		}
+		state.LastBytes += int64(len(data))
+		return decodeOSV(data)
+	}
+
+	var advisories []osvAdvisory
+	err = filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(file), ".json") {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if !since.IsZero() && !info.ModTime().After(since) {
+			return nil
+		}
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		state.LastBytes += int64(len(data))
+
+		parsed, err := decodeOSV(data)
+		if err != nil {
+			p.logger.Warning("Parser", fmt.Sprintf("Skipping %s: %v", file, err))
+			return nil
+		}
+		advisories = append(advisories, parsed...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OSV directory: %v", err)
+	}
+
+	return advisories, nil
+}
+
+// readOSVZip reads advisories from a zip dump such as the OSV all.zip exports
+func (p *Parser) readOSVZip(path string, state *models.FetchState) ([]osvAdvisory, error) {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open OSV zip: %v", err)
+	}
+	defer archive.Close()
+
+	var advisories []osvAdvisory
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() || !strings.EqualFold(filepath.Ext(file.Name), ".json") {
+			continue
+		}
+
+		reader, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", file.Name, err)
+		}
+		data, err := io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", file.Name, err)
+		}
+		state.LastBytes += int64(len(data))
+
+		parsed, err := decodeOSV(data)
+		if err != nil {
+			p.logger.Warning("Parser", fmt.Sprintf("Skipping %s: %v", file.Name, err))
+			continue
+		}
+		advisories = append(advisories, parsed...)
+	}
+
+	return advisories, nil
+}
+
+// decodeOSV decodes a single advisory, an array of advisories or an OSV
+// API response with a "vulns" array
+func decodeOSV(data []byte) ([]osvAdvisory, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	if data[0] == '[' {
+		var advisories []osvAdvisory
+		if err := json.Unmarshal(data, &advisories); err != nil {
+			return nil, fmt.Errorf("failed to parse OSV advisories: %v", err)
+		}
+		return advisories, nil
+	}
+
+	var wrapper struct {
+		Vulns []osvAdvisory `json:"vulns"`
+	}
+	if err := json.Unmarshal(data, &wrapper); err == nil && len(wrapper.Vulns) > 0 {
+		return wrapper.Vulns, nil
+	}
+
+	var advisory osvAdvisory
+	if err := json.Unmarshal(data, &advisory); err != nil {
+		return nil, fmt.Errorf("failed to parse OSV advisory: %v", err)
+	}
+	return []osvAdvisory{advisory}, nil
+}
+
+// osvItem converts an OSV advisory into an intelligence item
+func osvItem(source models.FeedSource, advisory osvAdvisory, retrieved time.Time) *models.Intelligence {
+	item := &models.Intelligence{
+		SourceID:  source.ID,
+		Category:  sourceCategory(source, models.CategoryCybersec),
+		URL:       osvDetailURL + advisory.ID,
+		Published: parseOSVTime(advisory.Published, retrieved),
+		Retrieved: retrieved,
+		Modified:  parseOSVTime(advisory.Modified, time.Time{}),
+		Aliases:   advisory.Aliases,
+	}
+
+	// Prefer the advisory's own CVE ID, then a CVE alias
+	if strings.HasPrefix(advisory.ID, "CVE-") {
+		item.CVEID = advisory.ID
+	}
+	for _, alias := range advisory.Aliases {
+		if item.CVEID == "" && strings.HasPrefix(alias, "CVE-") {
+			item.CVEID = alias
+		}
+	}
+
+	// Map affected packages and ranges
+	severity := advisory.DatabaseSpecific.Severity
+	for _, affected := range advisory.Affected {
+		pkg := models.AffectedPackage{
+			Ecosystem: affected.Package.Ecosystem,
+			Name:      affected.Package.Name,
+			Versions:  affected.Versions,
+		}
+		for _, r := range affected.Ranges {
+			pkg.Ranges = append(pkg.Ranges, osvRanges(r.Type, r.Events)...)
+		}
+		item.Affected = append(item.Affected, pkg)
+
+		if severity == "" {
+			severity = affected.DatabaseSpecific.Severity
+		}
+	}
+	item.Severity = normalizeSeverity(severity)
+
+	for _, sev := range advisory.Severity {
+		if strings.HasPrefix(sev.Type, "CVSS_") {
+			item.CVSSVector = sev.Score
+			break
+		}
+	}
+
+	// Build title from summary and affected packages
+	title := advisory.Summary
+	if title == "" {
+		title = firstSentence(advisory.Details, 120)
+	}
+	if len(item.Affected) > 0 {
+		title = fmt.Sprintf("%s (%s %s)", title, item.Affected[0].Ecosystem, item.Affected[0].Name)
+	}
+	item.Title = fmt.Sprintf("%s: %s", advisory.ID, title)
+
+	summary := advisory.Details
+	if summary == "" {
+		summary = advisory.Summary
+	}
+	item.Summary = cleanSummary(summary)
+
+	item.ID = generateID(item)
+	item.Hash = generateHash(item)
+	return item
+}
+
+// osvRanges converts OSV range events into version ranges
+func osvRanges(rangeType string, events []osvEvent) []models.VersionRange {
+	var ranges []models.VersionRange
+	var current *models.VersionRange
+
+	for _, event := range events {
+		switch {
+		case event.Introduced != "":
+			if current != nil {
+				ranges = append(ranges, *current)
+			}
+			current = &models.VersionRange{Type: rangeType, Introduced: event.Introduced}
+		case event.Fixed != "" && current != nil:
+			current.Fixed = event.Fixed
+			ranges = append(ranges, *current)
+			current = nil
+		case event.LastAffected != "" && current != nil:
+			current.LastAffected = event.LastAffected
+			ranges = append(ranges, *current)
+			current = nil
+		}
+	}
+
+	// A range with no end affects all later versions
+	if current != nil {
+		ranges = append(ranges, *current)
+	}
+	return ranges
+}
+
+// normalizeSeverity maps source severity labels to CRITICAL/HIGH/MEDIUM/LOW
+func normalizeSeverity(severity string) string {
+	switch strings.ToUpper(strings.TrimSpace(severity)) {
+	case "CRITICAL":
+		return "CRITICAL"
+	case "HIGH", "IMPORTANT":
+		return "HIGH"
+	case "MEDIUM", "MODERATE":
+		return "MEDIUM"
+	case "LOW":
+		return "LOW"
+	default:
+		return ""
+	}
+}
+
+// parseOSVTime parses an OSV RFC 3339 timestamp
+func parseOSVTime(value string, fallback time.Time) time.Time {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC()
+	}
+	return fallback
+}
+
+// localPath returns the filesystem path of a file:// URL or a plain path
+func localPath(source string) (string, bool) {
+	if strings.HasPrefix(source, "file://") {
+		return strings.TrimPrefix(source, "file://"), true
+	}
+	if strings.Contains(source, "://") {
+		return "", false
+	}
+	return source, true
+}
@@ -0,0 +1,87 @@
+// internal/feeds/osv_test.go
+package feeds
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+)
+
+// writeOSV writes an advisory to a JSON file in dir
+func writeOSV(t *testing.T, dir, id string, published time.Time) {
+	t.Helper()
+	advisory := map[string]interface{}{
+		"id":        id,
+		"published": published.Format(time.RFC3339),
+		"modified":  published.Format(time.RFC3339),
+		"summary":   "Prototype pollution",
+		"affected": []map[string]interface{}{
+			{"package": map[string]string{"ecosystem": "npm", "name": "lodash"}},
+		},
+	}
+	data, err := json.Marshal(advisory)
+	if err != nil {
+		t.Fatalf("failed to encode advisory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, id+".json"), data, 0644); err != nil {
+		t.Fatalf("failed to write advisory: %v", err)
+	}
+}
+
+func TestOSVDumpBackfill(t *testing.T) {
+	parser := newTestParser(t)
+	store := newTestStore(t)
+
+	dir := t.TempDir()
+	now := time.Now().UTC()
+	writeOSV(t, dir, "GHSA-old1-0000-0000", now.AddDate(-3, 0, 0))
+	writeOSV(t, dir, "GHSA-new1-0000-0000", now.Add(-time.Hour))
+
+	source := models.FeedSource{
+		ID:          "osv-dump",
+		Name:        "OSV dump",
+		URL:         dir,
+		Categories:  []models.Category{models.CategoryCybersec},
+		FetchMethod: "osv",
+	}
+
+	// The first read stores the whole dump, but posts only recent advisories
+	state := &models.FetchState{SourceID: source.ID}
+	items, err := parser.ParseFeed(source, state)
+	if err != nil {
+		t.Fatalf("failed to read dump: %v", err)
+	}
+	if count, err := store.SaveIntelligence(items); err != nil || count != 2 {
+		t.Fatalf("saved %d items (%v), want the whole dump", count, err)
+	}
+
+	queued, err := store.GetPendingAutoposts(10)
+	if err != nil {
+		t.Fatalf("failed to get autoposts: %v", err)
+	}
+	if len(queued) != 1 || !strings.HasPrefix(queued[0].Title, "GHSA-new1-0000-0000") {
+		t.Fatalf("queued %d autoposts, want only the recent advisory", len(queued))
+	}
+
+	// Advisories added to the dump later are posted, however old they are
+	writeOSV(t, dir, "GHSA-old2-0000-0000", now.AddDate(-2, 0, 0))
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(filepath.Join(dir, "GHSA-old2-0000-0000.json"), future, future); err != nil {
+		t.Fatalf("failed to touch advisory: %v", err)
+	}
+	items, err = parser.ParseFeed(source, state)
+	if err != nil {
+		t.Fatalf("failed to read dump: %v", err)
+	}
+	if count, err := store.SaveIntelligence(items); err != nil || count != 1 {
+		t.Fatalf("saved %d items (%v), want the added advisory", count, err)
+	}
+	if queued, _ = store.GetPendingAutoposts(10); len(queued) != 2 {
+		t.Fatalf("queued %d autoposts, want the added advisory too", len(queued))
+	}
+}
@@ -2,6 +2,7 @@
 package feeds
 
 import (
+	"bytes"
 	"crypto/md5"
 	"fmt"
 	"io"
@@ -9,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/NullMeDev/Infopulse-Node/internal/intel"
 	"github.com/NullMeDev/Infopulse-Node/internal/logger"
 	"github.com/NullMeDev/Infopulse-Node/internal/models"
 	"github.com/mmcdole/gofeed"
@@ -35,16 +37,74 @@ func NewParser(timeoutSeconds int, logger *logger.Logger) *Parser {
 	}
 }
 
-// ParseFeed fetches and parses a feed source
-func (p *Parser) ParseFeed(source models.FeedSource) ([]*models.Intelligence, error) {
+// ParseFeed fetches and parses a feed source. The fetch state is used for
+// conditional requests and is updated with the outcome of this fetch.
+func (p *Parser) ParseFeed(source models.FeedSource, state *models.FetchState) ([]*models.Intelligence, error) {
 	p.logger.Info("Parser", fmt.Sprintf("Fetching feed: %s (%s)", source.Name, source.URL))
 
+	if state == nil {
+		state = &models.FetchState{SourceID: source.ID}
+	}
+
+	// Record the fetch time once the fetch completes so fetch methods can
+	// still see when the previous fetch happened
+	started := time.Now().UTC()
+	defer func() {
+		state.LastFetched = started
+		if !state.Resume.IsZero() {
+			state.LastFetched, state.Resume = state.Resume, time.Time{}
+		}
+	}()
+	state.LastBytes = 0
+
 	var items []*models.Intelligence
 
 	// Handle different fetch methods
 	switch strings.ToLower(source.FetchMethod) {
 	case "rss":
-		parsedItems, err := p.parseRSS(source)
+		parsedItems, err := p.parseRSS(source, state)
+		if err != nil {
+			return nil, err
+		}
+		items = parsedItems
+	case "nvd":
+		parsedItems, err := p.parseNVD(source, state)
+		if err != nil {
+			return nil, err
+		}
+		items = parsedItems
+	case "cisa-kev":
+		parsedItems, err := p.parseKEV(source, state)
+		if err != nil {
+			return nil, err
+		}
+		items = parsedItems
+	case "osv":
+		parsedItems, err := p.parseOSV(source, state)
+		if err != nil {
+			return nil, err
+		}
+		items = parsedItems
+	case "html":
+		parsedItems, err := p.parseHTML(source, state)
+		if err != nil {
+			return nil, err
+		}
+		items = parsedItems
+	case "json":
+		parsedItems, err := p.parseJSON(source, state)
+		if err != nil {
+			return nil, err
+		}
+		items = parsedItems
+	case "releases":
+		parsedItems, err := p.parseReleases(source, state)
+		if err != nil {
+			return nil, err
+		}
+		items = parsedItems
+	case "mbox":
+		parsedItems, err := p.parseMbox(source, state)
 		if err != nil {
 			return nil, err
 		}
@@ -58,29 +118,100 @@ func (p *Parser) ParseFeed(source models.FeedSource) ([]*models.Intelligence, er
 	return items, nil
 }
 
-// parseRSS fetches and parses an RSS feed
-func (p *Parser) parseRSS(source models.FeedSource) ([]*models.Intelligence, error) {
-	// Fetch the feed content
-	resp, err := p.client.Get(source.URL)
+// fetch performs a conditional GET using the validators in state and
+// records the outcome in state. A nil body with a nil error means the
+// content has not changed since the last fetch.
+func (p *Parser) fetch(url string, state *models.FetchState) ([]byte, error) {
+	return p.fetchURL(url, state, true)
+}
+
+// fetchURL performs a GET and records the outcome in state. Validators are
+// only sent and stored for conditional requests, since they don't apply to
+// query URLs that change between fetches.
+func (p *Parser) fetchURL(url string, state *models.FetchState, conditional bool) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	// Send validators from the previous fetch
+	if conditional && state.ETag != "" {
+		req.Header.Set("If-None-Match", state.ETag)
+	}
+	if conditional && state.LastModified != "" {
+		req.Header.Set("If-Modified-Since", state.LastModified)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		state.LastStatus = 0
+		state.LastError = err.Error()
 		return nil, fmt.Errorf("failed to fetch feed: %v", err)
 	}
 	defer resp.Body.Close()
 
+	state.LastStatus = resp.StatusCode
+
+	// Unchanged since the last fetch
+	if conditional && resp.StatusCode == http.StatusNotModified {
+		state.LastError = ""
+		return nil, nil
+	}
+
 	// Check status code
 	if resp.StatusCode != http.StatusOK {
+		state.LastError = fmt.Sprintf("HTTP %d", resp.StatusCode)
 		return nil, fmt.Errorf("failed to fetch feed: HTTP %d", resp.StatusCode)
 	}
 
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		state.LastError = err.Error()
+		return nil, fmt.Errorf("failed to read feed: %v", err)
+	}
+
+	// Byte counts accumulate across the pages of a single fetch
+	state.LastBytes += int64(len(body))
+	state.LastError = ""
+	if conditional {
+		state.ETag = resp.Header.Get("ETag")
+		state.LastModified = resp.Header.Get("Last-Modified")
+	}
+
+	return body, nil
+}
+
+// parseRSS fetches and parses an RSS feed
+func (p *Parser) parseRSS(source models.FeedSource, state *models.FetchState) ([]*models.Intelligence, error) {
+	// Feeds delivered as files, e.g. to offline deployments
+	if path, ok := localPath(source.URL); ok {
+		return p.parseLocalFeeds(source, path, state)
+	}
+
+	// Fetch the feed content
+	body, err := p.fetch(source.URL, state)
+	if err != nil {
+		return nil, err
+	}
+
+	// Not modified, so there are no new items
+	if body == nil {
+		return nil, nil
+	}
+
 	// Parse the feed
-	feed, err := p.feedParser.Parse(resp.Body)
+	feed, err := p.feedParser.Parse(bytes.NewReader(body))
 	if err != nil {
+		state.LastError = err.Error()
 		return nil, fmt.Errorf("failed to parse feed: %v", err)
 	}
 
-	// Process items
+	return feedItems(source, feed, time.Now().UTC()), nil
+}
+
+// feedItems converts the items of a parsed feed into intelligence items
+func feedItems(source models.FeedSource, feed *gofeed.Feed, now time.Time) []*models.Intelligence {
 	var items []*models.Intelligence
-	now := time.Now().UTC()
 
 	for _, feedItem := range feed.Items {
 		// Create intelligence item
@@ -118,7 +249,26 @@ func (p *Parser) parseRSS(source models.FeedSource) ([]*models.Intelligence, err
 		items = append(items, item)
 	}
 
-	return items, nil
+	return items
+}
+
+// backfillLookback is how far back the items of a source's first fetch are
+// still delivered
+const backfillLookback = 7 * 24 * time.Hour
+
+// markBackfill marks the items of a source's first fetch that were
+// published before the lookback, so that catalogs and dumps are stored
+// without being delivered in full
+func markBackfill(items []*models.Intelligence, state *models.FetchState) {
+	if !state.LastFetched.IsZero() {
+		return
+	}
+	cutoff := time.Now().Add(-backfillLookback)
+	for _, item := range items {
+		if item.Published.Before(cutoff) {
+			item.Backfill = true
+		}
+	}
 }
 
 // cleanSummary cleans HTML and truncates the summary
@@ -131,29 +281,39 @@ func cleanSummary(text string) string {
 	return text
 }
 
-// generateID creates a unique ID for an item
+// generateID creates a unique ID for an item. The title and URL are
+// normalized first, as the normalize stage will store them.
 func generateID(item *models.Intelligence) string {
+	title, url := intel.NormalizeTitleURL(item.Title, item.URL)
+
 	// Use URL as basis for ID if available
-	if item.URL != "" {
-		hash := md5.Sum([]byte(item.URL))
+	if url != "" {
+		hash := md5.Sum([]byte(url))
 		return fmt.Sprintf("%x", hash)[:12]
 	}
 
 	// Fallback to title and timestamp
-	hash := md5.Sum([]byte(item.Title + item.Published.String()))
+	hash := md5.Sum([]byte(title + item.Published.String()))
 	return fmt.Sprintf("%x", hash)[:12]
 }
 
-// generateHash creates a hash for deduplication
+// generateHash creates a hash for deduplication from the normalized title
+// and URL
 func generateHash(item *models.Intelligence) string {
-	hash := md5.Sum([]byte(item.Title + item.URL))
+	title, url := intel.NormalizeTitleURL(item.Title, item.URL)
+	hash := md5.Sum([]byte(title + url))
 	return fmt.Sprintf("%x", hash)
 }
 
 // extractSeverity extracts severity information from feed item
 func extractSeverity(feedItem *gofeed.Item) string {
+	return severityFromTitle(feedItem.Title)
+}
+
+// severityFromTitle finds a severity level named in a title
+func severityFromTitle(title string) string {
 	// Check for CVE severity in title
-	title := strings.ToUpper(feedItem.Title)
+	title = strings.ToUpper(title)
 	
 	if strings.Contains(title, "CRITICAL") {
 		return "CRITICAL"
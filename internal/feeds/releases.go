@@ -0,0 +1,427 @@
+// internal/feeds/releases.go
+package feeds
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+	"github.com/NullMeDev/Infopulse-Node/pkg/utils"
+)
+
+const (
+	// releasesPerPage is how many of each repository's latest releases are
+	// requested per fetch
+	releasesPerPage = 20
+	// releasesLookback is how far back releases still count as new. Items
+	// are deduplicated by ID, so the margin only guards against missed
+	// fetches.
+	releasesLookback = 7 * 24 * time.Hour
+
+	// Public APIs used when a releases source has no URL
+	githubAPI = "https://api.github.com"
+	gitlabAPI = "https://gitlab.com/api/v4"
+)
+
+// release is a release or tag of any provider
+type release struct {
+	Tag        string
+	Name       string
+	URL        string
+	Notes      string
+	Published  time.Time // Zero for tags without a date
+	Prerelease bool
+}
+
+// githubRelease is a release from the GitHub REST API
+type githubRelease struct {
+	TagName     string `json:"tag_name"`
+	Name        string `json:"name"`
+	HTMLURL     string `json:"html_url"`
+	Body        string `json:"body"`
+	Draft       bool   `json:"draft"`
+	Prerelease  bool   `json:"prerelease"`
+	PublishedAt string `json:"published_at"`
+}
+
+// githubTag is a tag from the GitHub REST API
+type githubTag struct {
+	Name string `json:"name"`
+}
+
+// gitlabRelease is a release from the GitLab REST API
+type gitlabRelease struct {
+	TagName         string `json:"tag_name"`
+	Name            string `json:"name"`
+	Description     string `json:"description"`
+	ReleasedAt      string `json:"released_at"`
+	UpcomingRelease bool   `json:"upcoming_release"`
+	Links           struct {
+		Self string `json:"self"`
+	} `json:"_links"`
+}
+
+// gitlabTag is a tag from the GitLab REST API
+type gitlabTag struct {
+	Name    string `json:"name"`
+	Message string `json:"message"`
+	Commit  struct {
+		CreatedAt string `json:"created_at"`
+	} `json:"commit"`
+}
+
+// parseReleases fetches the latest releases or tags of each watched
+// repository. The first fetch of a source only reports the newest recent
+// release of each repository, so adding a source doesn't flood its channel.
+func (p *Parser) parseReleases(source models.FeedSource, state *models.FetchState) ([]*models.Intelligence, error) {
+	watch := source.Releases
+	if watch == nil || len(watch.Repositories) == 0 {
+		return nil, fmt.Errorf("releases source %s needs repositories", source.ID)
+	}
+
+	provider := strings.ToLower(watch.Provider)
+	if provider == "" {
+		provider = "github"
+	}
+
+	base := strings.TrimRight(source.URL, "/")
+	switch provider {
+	case "github":
+		if base == "" {
+			base = githubAPI
+		}
+	case "gitlab":
+		if base == "" {
+			base = gitlabAPI
+		}
+	default:
+		return nil, fmt.Errorf("unsupported release provider: %s", watch.Provider)
+	}
+
+	var items []*models.Intelligence
+	now := time.Now().UTC()
+	firstFetch := state.LastFetched.IsZero()
+	failed := 0
+	var lastErr error
+
+	for _, repo := range watch.Repositories {
+		repo = strings.Trim(strings.TrimSpace(repo), "/")
+
+		var releases []release
+		var err error
+		if provider == "gitlab" {
+			releases, err = p.fetchGitLabReleases(base, repo, watch.Tags, state)
+		} else {
+			releases, err = p.fetchGitHubReleases(base, repo, watch.Tags, state)
+		}
+		if err != nil {
+			p.logger.Warning("Parser", fmt.Sprintf("Failed to fetch releases of %s: %v", repo, err))
+			failed++
+			lastErr = err
+			continue
+		}
+
+		items = append(items, releaseItems(source, repo, releases, firstFetch, now)...)
+	}
+
+	// One unreachable repository shouldn't hold back the others
+	if lastErr != nil {
+		if failed == len(watch.Repositories) {
+			return nil, lastErr
+		}
+		state.LastError = fmt.Sprintf("%d of %d repositories failed: %v", failed, len(watch.Repositories), lastErr)
+	}
+
+	return items, nil
+}
+
+// fetchGitHubReleases fetches the latest releases or tags of a GitHub
+// repository, skipping drafts
+func (p *Parser) fetchGitHubReleases(base, repo string, tags bool, state *models.FetchState) ([]release, error) {
+	web := githubWebURL(base)
+
+	if tags {
+		body, err := p.fetchURL(fmt.Sprintf("%s/repos/%s/tags?per_page=%d", base, repo, releasesPerPage), state, false)
+		if err != nil {
+			return nil, err
+		}
+		var resp []githubTag
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return nil, fmt.Errorf("failed to parse tags: %v", err)
+		}
+
+		releases := make([]release, 0, len(resp))
+		for _, tag := range resp {
+			releases = append(releases, release{
+				Tag:        tag.Name,
+				URL:        fmt.Sprintf("%s/%s/releases/tag/%s", web, repo, url.PathEscape(tag.Name)),
+				Prerelease: isPrerelease(tag.Name),
+			})
+		}
+		return releases, nil
+	}
+
+	body, err := p.fetchURL(fmt.Sprintf("%s/repos/%s/releases?per_page=%d", base, repo, releasesPerPage), state, false)
+	if err != nil {
+		return nil, err
+	}
+	var resp []githubRelease
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse releases: %v", err)
+	}
+
+	releases := make([]release, 0, len(resp))
+	for _, r := range resp {
+		if r.Draft {
+			continue
+		}
+		releases = append(releases, release{
+			Tag:        r.TagName,
+			Name:       r.Name,
+			URL:        r.HTMLURL,
+			Notes:      r.Body,
+			Published:  parseReleaseTime(r.PublishedAt),
+			Prerelease: r.Prerelease,
+		})
+	}
+	return releases, nil
+}
+
+// fetchGitLabReleases fetches the latest releases or tags of a GitLab
+// project
+func (p *Parser) fetchGitLabReleases(base, repo string, tags bool, state *models.FetchState) ([]release, error) {
+	web := strings.TrimSuffix(base, "/api/v4")
+	project := url.PathEscape(repo)
+
+	if tags {
+		body, err := p.fetchURL(fmt.Sprintf("%s/projects/%s/repository/tags?per_page=%d", base, project, releasesPerPage), state, false)
+		if err != nil {
+			return nil, err
+		}
+		var resp []gitlabTag
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return nil, fmt.Errorf("failed to parse tags: %v", err)
+		}
+
+		releases := make([]release, 0, len(resp))
+		for _, tag := range resp {
+			releases = append(releases, release{
+				Tag:        tag.Name,
+				URL:        fmt.Sprintf("%s/%s/-/tags/%s", web, repo, url.PathEscape(tag.Name)),
+				Notes:      tag.Message,
+				Published:  parseReleaseTime(tag.Commit.CreatedAt),
+				Prerelease: isPrerelease(tag.Name),
+			})
+		}
+		return releases, nil
+	}
+
+	body, err := p.fetchURL(fmt.Sprintf("%s/projects/%s/releases?per_page=%d", base, project, releasesPerPage), state, false)
+	if err != nil {
+		return nil, err
+	}
+	var resp []gitlabRelease
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse releases: %v", err)
+	}
+
+	releases := make([]release, 0, len(resp))
+	for _, r := range resp {
+		link := r.Links.Self
+		if link == "" {
+			link = fmt.Sprintf("%s/%s/-/releases/%s", web, repo, url.PathEscape(r.TagName))
+		}
+		releases = append(releases, release{
+			Tag:        r.TagName,
+			Name:       r.Name,
+			URL:        link,
+			Notes:      r.Description,
+			Published:  parseReleaseTime(r.ReleasedAt),
+			Prerelease: r.UpcomingRelease || isPrerelease(r.TagName),
+		})
+	}
+	return releases, nil
+}
+
+// releaseItems converts the new releases of a repository into items
+func releaseItems(source models.FeedSource, repo string, releases []release, firstFetch bool, now time.Time) []*models.Intelligence {
+	watch := source.Releases
+
+	// Newest first: by date where both have one, otherwise by version
+	sort.SliceStable(releases, func(i, j int) bool {
+		a, b := releases[i], releases[j]
+		if !a.Published.IsZero() && !b.Published.IsZero() && !a.Published.Equal(b.Published) {
+			return a.Published.After(b.Published)
+		}
+		return utils.CompareVersions(a.Tag, b.Tag) > 0
+	})
+
+	// Walk from the oldest release so each one can be compared with the
+	// highest stable version before it. Releases older than the page are
+	// unknown, so the oldest one is only the baseline and is never reported.
+	var items []*models.Intelligence
+	previous := ""
+	for i := len(releases) - 1; i >= 0; i-- {
+		r := releases[i]
+		if r.Tag == "" || (r.Prerelease && watch.SkipPrereleases) {
+			continue
+		}
+		if previous == "" {
+			previous = r.Tag
+			continue
+		}
+
+		bump := majorMinorBump(previous, r.Tag)
+		if !r.Prerelease && utils.CompareVersions(r.Tag, previous) > 0 {
+			previous = r.Tag
+		}
+
+		if watch.MajorMinorOnly && !bump {
+			continue
+		}
+		if !r.Published.IsZero() && r.Published.Before(now.Add(-releasesLookback)) {
+			continue
+		}
+
+		items = append(items, releaseItem(source, repo, r, now))
+	}
+
+	// Items were collected oldest first
+	for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+		items[i], items[j] = items[j], items[i]
+	}
+	if firstFetch && len(items) > 1 {
+		items = items[:1]
+	}
+	return items
+}
+
+// releaseItem converts a release into an intelligence item
+func releaseItem(source models.FeedSource, repo string, r release, retrieved time.Time) *models.Intelligence {
+	title := fmt.Sprintf("%s %s", repo, r.Tag)
+	if name := strings.TrimSpace(r.Name); name != "" && !strings.Contains(name, r.Tag) {
+		title += ": " + name
+	}
+	if r.Prerelease {
+		title += " (pre-release)"
+	}
+
+	item := &models.Intelligence{
+		SourceID:   source.ID,
+		Category:   sourceCategory(source, models.CategoryOpenSource),
+		Title:      title,
+		URL:        r.URL,
+		Summary:    cleanSummary(markdownToText(r.Notes)),
+		Published:  r.Published,
+		Retrieved:  retrieved,
+		Version:    r.Tag,
+		Prerelease: r.Prerelease,
+	}
+	if item.Published.IsZero() {
+		item.Published = retrieved
+	}
+
+	item.ID = generateID(item)
+	item.Hash = generateHash(item)
+	return item
+}
+
+// githubWebURL returns the web address of a GitHub API base: github.com for
+// the public API, and the host itself for GitHub Enterprise
+func githubWebURL(base string) string {
+	if base == githubAPI {
+		return "https://github.com"
+	}
+	return strings.TrimSuffix(base, "/api/v3")
+}
+
+// parseReleaseTime parses an RFC 3339 release date, returning the zero time
+// if it is missing
+func parseReleaseTime(value string) time.Time {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC()
+	}
+	return time.Time{}
+}
+
+// isPrerelease reports whether a version has a semver pre-release suffix,
+// such as 1.2.0-rc.1
+func isPrerelease(version string) bool {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	version, _, _ = strings.Cut(version, "+")
+	core, pre, found := strings.Cut(version, "-")
+	return found && pre != "" && core != "" && core[0] >= '0' && core[0] <= '9'
+}
+
+// majorMinorBump reports whether version raises the major or minor version
+// of previous
+func majorMinorBump(previous, version string) bool {
+	return utils.CompareVersions(majorMinor(version), majorMinor(previous)) > 0
+}
+
+// majorMinor truncates a version to its major and minor components
+func majorMinor(version string) string {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	version, _, _ = strings.Cut(version, "+")
+	version, _, _ = strings.Cut(version, "-")
+
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) > 2 {
+		parts = parts[:2]
+	}
+	return strings.Join(parts, ".")
+}
+
+var (
+	markdownImage    = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	markdownLink     = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	markdownHeading  = regexp.MustCompile(`^#{1,6}\s+`)
+	markdownBullet   = regexp.MustCompile(`^[*+-]\s+`)
+	markdownEmphasis = regexp.MustCompile("\\*\\*|__|~~|`")
+	htmlTag          = regexp.MustCompile(`<[^>]+>`)
+)
+
+// markdownToText renders release notes written in Markdown as plain text,
+// keeping line breaks and list items but dropping markup
+func markdownToText(markdown string) string {
+	var lines []string
+	blank := false
+
+	for _, line := range strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+
+		// Code fences and horizontal rules have no text of their own
+		if strings.HasPrefix(line, "```") || strings.HasPrefix(line, "~~~") ||
+			line == "---" || line == "***" || line == "___" {
+			continue
+		}
+
+		line = strings.TrimSpace(strings.TrimLeft(line, ">"))
+		line = markdownHeading.ReplaceAllString(line, "")
+		line = markdownBullet.ReplaceAllString(line, "- ")
+		line = markdownImage.ReplaceAllString(line, "$1")
+		line = markdownLink.ReplaceAllString(line, "$1")
+		line = htmlTag.ReplaceAllString(line, "")
+		line = markdownEmphasis.ReplaceAllString(line, "")
+		line = strings.TrimSpace(html.UnescapeString(line))
+
+		// Collapse runs of blank lines into one
+		if line == "" {
+			blank = len(lines) > 0
+			continue
+		}
+		if blank {
+			lines = append(lines, "")
+			blank = false
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
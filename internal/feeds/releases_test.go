@@ -0,0 +1,194 @@
+// internal/feeds/releases_test.go
+package feeds
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+)
+
+// serveReleases serves a GitHub releases page and a GitLab releases page
+// for one repository each, with releases published over the last days
+func serveReleases(t *testing.T) *httptest.Server {
+	t.Helper()
+	now := time.Now().UTC()
+	daysAgo := func(days int) string {
+		return now.Add(-time.Duration(days) * 24 * time.Hour).Format(time.RFC3339)
+	}
+
+	github := []map[string]interface{}{
+		{"tag_name": "v1.4.0", "name": "v1.4.0", "html_url": "https://github.com/acme/tool/releases/tag/v1.4.0", "draft": true, "published_at": ""},
+		{"tag_name": "v1.4.0-rc.1", "name": "v1.4.0-rc.1", "html_url": "https://github.com/acme/tool/releases/tag/v1.4.0-rc.1", "prerelease": true, "published_at": daysAgo(0)},
+		{"tag_name": "v1.3.0", "name": "Tool 1.3", "html_url": "https://github.com/acme/tool/releases/tag/v1.3.0", "body": "## Changes\n\n* **New** exporter", "published_at": daysAgo(1)},
+		{"tag_name": "v1.2.1", "name": "v1.2.1", "html_url": "https://github.com/acme/tool/releases/tag/v1.2.1", "published_at": daysAgo(2)},
+		{"tag_name": "v1.2.0", "name": "v1.2.0", "html_url": "https://github.com/acme/tool/releases/tag/v1.2.0", "published_at": daysAgo(3)},
+	}
+
+	gitlab := []map[string]interface{}{
+		{"tag_name": "2.1.0", "name": "2.1.0", "description": "Minor release", "released_at": daysAgo(1),
+			"_links": map[string]string{"self": "https://gitlab.example.com/group/project/-/releases/2.1.0"}},
+		{"tag_name": "2.0.1", "name": "2.0.1", "released_at": daysAgo(2)},
+		{"tag_name": "2.0.0", "name": "2.0.0", "released_at": daysAgo(3)},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/acme/tool/releases", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(github)
+	})
+	mux.HandleFunc("/api/v4/projects/", func(w http.ResponseWriter, r *http.Request) {
+		// The project path is sent as a single escaped segment
+		if r.URL.EscapedPath() != "/api/v4/projects/group%2Fproject/releases" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(gitlab)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// fetchReleases fetches a releases source as a repeat fetch, or as the
+// first fetch of the source, and returns the reported versions
+func fetchReleases(t *testing.T, parser *Parser, source models.FeedSource, firstFetch bool) ([]string, []*models.Intelligence) {
+	t.Helper()
+	state := &models.FetchState{SourceID: source.ID}
+	if !firstFetch {
+		state.LastFetched = time.Now().Add(-time.Hour)
+	}
+
+	items, err := parser.ParseFeed(source, state)
+	if err != nil {
+		t.Fatalf("failed to fetch releases: %v", err)
+	}
+
+	versions := make([]string, 0, len(items))
+	for _, item := range items {
+		versions = append(versions, item.Version)
+	}
+	return versions, items
+}
+
+// equalVersions reports whether two version lists are the same, in order
+func equalVersions(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestGitHubReleases(t *testing.T) {
+	parser := newTestParser(t)
+	server := serveReleases(t)
+
+	source := models.FeedSource{
+		ID:          "acme-releases",
+		Name:        "Acme releases",
+		URL:         server.URL,
+		Categories:  []models.Category{models.CategoryOpenSource},
+		FetchMethod: "releases",
+		Releases: &models.ReleaseWatch{
+			Provider:     "github",
+			Repositories: []string{"acme/tool"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		watch      models.ReleaseWatch
+		firstFetch bool
+		want       []string
+	}{
+		{
+			// Drafts are skipped and the oldest release is only a baseline
+			name: "all releases",
+			want: []string{"v1.4.0-rc.1", "v1.3.0", "v1.2.1"},
+		},
+		{
+			name:  "skip pre-releases",
+			watch: models.ReleaseWatch{SkipPrereleases: true},
+			want:  []string{"v1.3.0", "v1.2.1"},
+		},
+		{
+			name:  "major and minor releases only",
+			watch: models.ReleaseWatch{SkipPrereleases: true, MajorMinorOnly: true},
+			want:  []string{"v1.3.0"},
+		},
+		{
+			name:       "first fetch",
+			watch:      models.ReleaseWatch{SkipPrereleases: true},
+			firstFetch: true,
+			want:       []string{"v1.3.0"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			watch := test.watch
+			watch.Provider = source.Releases.Provider
+			watch.Repositories = source.Releases.Repositories
+			source := source
+			source.Releases = &watch
+
+			versions, items := fetchReleases(t, parser, source, test.firstFetch)
+			if !equalVersions(versions, test.want) {
+				t.Fatalf("versions = %v, want %v", versions, test.want)
+			}
+
+			for _, item := range items {
+				if item.Version == "v1.3.0" {
+					if item.Title != "acme/tool v1.3.0: Tool 1.3" {
+						t.Errorf("title = %q", item.Title)
+					}
+					if item.Summary != "Changes\n\n- New exporter" {
+						t.Errorf("summary = %q", item.Summary)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGitLabReleases(t *testing.T) {
+	parser := newTestParser(t)
+	server := serveReleases(t)
+
+	source := models.FeedSource{
+		ID:          "project-releases",
+		Name:        "Project releases",
+		URL:         server.URL + "/api/v4",
+		Categories:  []models.Category{models.CategoryOpenSource},
+		FetchMethod: "releases",
+		Releases: &models.ReleaseWatch{
+			Provider:     "gitlab",
+			Repositories: []string{"group/project"},
+		},
+	}
+
+	versions, items := fetchReleases(t, parser, source, false)
+	if want := []string{"2.1.0", "2.0.1"}; !equalVersions(versions, want) {
+		t.Fatalf("versions = %v, want %v", versions, want)
+	}
+	if items[0].URL != "https://gitlab.example.com/group/project/-/releases/2.1.0" {
+		t.Errorf("URL = %q, want the release's own link", items[0].URL)
+	}
+	if want := server.URL + "/group/project/-/releases/2.0.1"; items[1].URL != want {
+		t.Errorf("URL = %q, want %q", items[1].URL, want)
+	}
+
+	source.Releases.MajorMinorOnly = true
+	versions, _ = fetchReleases(t, parser, source, false)
+	if want := []string{"2.1.0"}; !equalVersions(versions, want) {
+		t.Fatalf("major and minor versions = %v, want %v", versions, want)
+	}
+}
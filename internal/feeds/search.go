@@ -0,0 +1,170 @@
+// internal/feeds/search.go
+package feeds
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+)
+
+// likeEscaper escapes LIKE wildcards for use with ESCAPE '\'
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// initSearch creates the full-text index over item titles and summaries.
+// FTS5 is only compiled into the SQLite driver with the sqlite_fts5 build
+// tag; without it search falls back to substring matching.
+func (s *Store) initSearch() error {
+	var indexExists int
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'intel_search'`).Scan(&indexExists)
+	if err != nil {
+		return fmt.Errorf("failed to inspect schema: %v", err)
+	}
+
+	_, err = s.db.Exec(`
+	CREATE VIRTUAL TABLE IF NOT EXISTS intel_search USING fts5(
+		id UNINDEXED,
+		title,
+		summary,
+		tokenize = 'porter unicode61'
+	)`)
+	if err != nil {
+		s.logger.Warning("Store", fmt.Sprintf("Full-text search unavailable, using substring search (build with -tags sqlite_fts5 to enable): %v", err))
+		return nil
+	}
+	s.fullText = true
+
+	// Index items stored before the index existed
+	if indexExists == 0 {
+		result, err := s.db.Exec(`
+		INSERT INTO intel_search (id, title, summary)
+		SELECT id, title, COALESCE(summary, '') FROM intelligence`)
+		if err != nil {
+			return fmt.Errorf("failed to build search index: %v", err)
+		}
+		if indexed, err := result.RowsAffected(); err == nil && indexed > 0 {
+			s.logger.Info("Store", fmt.Sprintf("Indexed %d existing items for search", indexed))
+		}
+	}
+
+	return nil
+}
+
+// Search finds items matching a query, best matches first. Words must all
+// match; double-quoted phrases match exactly and OR between terms matches
+// either. Offset skips that many results for paging.
+func (s *Store) Search(query string, filter IntelFilter, limit, offset int) ([]*models.Intelligence, error) {
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("search query is empty")
+	}
+
+	conditions, args := filterConditions(filter)
+
+	var sqlQuery string
+	if s.fullText {
+		sqlQuery = `SELECT ` + intelligenceColumns + ` FROM intelligence
+		JOIN (
+			SELECT id AS match_id, bm25(intel_search, 0, 5, 1) AS rank
+			FROM intel_search WHERE intel_search MATCH ?
+		) matches ON matches.match_id = intelligence.id
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY matches.rank, published DESC
+		LIMIT ? OFFSET ?`
+		args = append([]interface{}{ftsQuery(terms)}, args...)
+	} else {
+		conditions = append(conditions, likeConditions(terms, &args))
+		sqlQuery = `SELECT ` + intelligenceColumns + ` FROM intelligence
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY published DESC
+		LIMIT ? OFFSET ?`
+	}
+	args = append(args, limit, offset)
+
+	rows, err := s.db.Query(sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search intelligence: %v", err)
+	}
+	items := s.scanIntelligence(rows)
+	rows.Close()
+
+	if err := s.loadRelated(items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// searchTerms splits a query into groups of alternatives: every group must
+// match, and a group matches if any of its words or double-quoted phrases
+// does. Words joined by OR form a group.
+func searchTerms(query string) [][]string {
+	var words []string
+	var current strings.Builder
+	inPhrase := false
+
+	flush := func() {
+		if word := strings.TrimSpace(current.String()); word != "" {
+			words = append(words, word)
+		}
+		current.Reset()
+	}
+
+	for _, r := range query {
+		switch {
+		case r == '"':
+			flush()
+			inPhrase = !inPhrase
+		case unicode.IsSpace(r) && !inPhrase:
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+
+	var groups [][]string
+	joinNext := false
+	for _, word := range words {
+		if word == "OR" {
+			joinNext = len(groups) > 0
+			continue
+		}
+		if joinNext {
+			groups[len(groups)-1] = append(groups[len(groups)-1], word)
+		} else {
+			groups = append(groups, []string{word})
+		}
+		joinNext = false
+	}
+	return groups
+}
+
+// ftsQuery builds an FTS5 query with every term quoted, so punctuation in
+// user input cannot be taken as query syntax
+func ftsQuery(groups [][]string) string {
+	parts := make([]string, 0, len(groups))
+	for _, group := range groups {
+		quoted := make([]string, 0, len(group))
+		for _, term := range group {
+			quoted = append(quoted, `"`+strings.ReplaceAll(term, `"`, `""`)+`"`)
+		}
+		parts = append(parts, "("+strings.Join(quoted, " OR ")+")")
+	}
+	return strings.Join(parts, " AND ")
+}
+
+// likeConditions builds substring conditions for search without FTS5
+func likeConditions(groups [][]string, args *[]interface{}) string {
+	clauses := make([]string, 0, len(groups))
+	for _, group := range groups {
+		alternatives := make([]string, 0, len(group))
+		for _, term := range group {
+			pattern := "%" + likeEscaper.Replace(term) + "%"
+			alternatives = append(alternatives, `title LIKE ? ESCAPE '\' OR summary LIKE ? ESCAPE '\'`)
+			*args = append(*args, pattern, pattern)
+		}
+		clauses = append(clauses, "("+strings.Join(alternatives, " OR ")+")")
+	}
+	return strings.Join(clauses, " AND ")
+}
@@ -0,0 +1,166 @@
+// internal/feeds/sinks.go
+package feeds
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+	"github.com/NullMeDev/Infopulse-Node/internal/subscriptions"
+)
+
+// SinkRoute selects the new items queued for an outbound sink
+type SinkRoute struct {
+	ID         string
+	Categories []models.Category         // Primary or secondary categories delivered (empty for all)
+	Filter     models.SubscriptionFilter // Conditions items must also meet
+}
+
+// Matches reports whether an item should be delivered to the sink
+func (r SinkRoute) Matches(item *models.Intelligence) bool {
+	if len(r.Categories) > 0 {
+		found := false
+		for _, category := range r.Categories {
+			if subscriptions.HasCategory(item, category) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return subscriptions.Empty(r.Filter) || subscriptions.Matches(r.Filter, item)
+}
+
+// initSinks creates the outbound sink delivery queue
+func (s *Store) initSinks() error {
+	_, err := s.db.Exec(`
+	CREATE TABLE IF NOT EXISTS sink_queue (
+		sink_id TEXT NOT NULL,
+		intel_id TEXT NOT NULL,
+		queued TIMESTAMP NOT NULL,
+		attempts INTEGER NOT NULL DEFAULT 0,
+		next_attempt TIMESTAMP NOT NULL,
+		delivered TIMESTAMP,
+		failed TIMESTAMP,
+		last_error TEXT NOT NULL DEFAULT '',
+		PRIMARY KEY (sink_id, intel_id)
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create sink queue table: %v", err)
+	}
+
+	_, err = s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_sink_queue_next ON sink_queue(next_attempt)`)
+	if err != nil {
+		return fmt.Errorf("failed to create sink queue index: %v", err)
+	}
+
+	return nil
+}
+
+// SetSinkRoutes sets the sinks that new items are queued for. Call it
+// before items are saved.
+func (s *Store) SetSinkRoutes(routes []SinkRoute) {
+	s.sinkRoutes = routes
+}
+
+// GetPendingSinkDeliveries retrieves queued deliveries to configured sinks
+// whose next attempt is due, oldest first. Sinks in skip are left out, so
+// the deliveries of paused sinks don't fill the batch.
+func (s *Store) GetPendingSinkDeliveries(now time.Time, limit int, skip map[string]bool) ([]*models.SinkDelivery, error) {
+	// Deliveries to sinks removed from the configuration are left alone
+	placeholders := make([]string, 0, len(s.sinkRoutes))
+	args := make([]interface{}, 0, len(s.sinkRoutes)+2)
+	for _, route := range s.sinkRoutes {
+		if skip[route.ID] {
+			continue
+		}
+		placeholders = append(placeholders, "?")
+		args = append(args, route.ID)
+	}
+	if len(placeholders) == 0 {
+		return nil, nil
+	}
+	args = append(args, now.UTC(), limit)
+
+	rows, err := s.db.Query(`
+	SELECT sink_id, intel_id, attempts
+	FROM sink_queue
+	WHERE sink_id IN (`+strings.Join(placeholders, ", ")+`)
+	AND delivered IS NULL AND failed IS NULL AND next_attempt <= ?
+	ORDER BY queued ASC
+	LIMIT ?`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sink queue: %v", err)
+	}
+
+	type pending struct {
+		sinkID   string
+		intelID  string
+		attempts int
+	}
+	var queued []pending
+	for rows.Next() {
+		var p pending
+		if err := rows.Scan(&p.sinkID, &p.intelID, &p.attempts); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan sink delivery: %v", err)
+		}
+		queued = append(queued, p)
+	}
+	rows.Close()
+
+	var deliveries []*models.SinkDelivery
+	for _, p := range queued {
+		item, err := s.GetIntelligenceByID(p.intelID)
+		if err != nil {
+			return nil, err
+		}
+		if item == nil {
+			continue
+		}
+
+		deliveries = append(deliveries, &models.SinkDelivery{
+			SinkID:   p.sinkID,
+			Item:     item,
+			Attempts: p.attempts,
+		})
+	}
+
+	return deliveries, nil
+}
+
+// MarkSinkDelivered records that an item has been delivered to a sink
+func (s *Store) MarkSinkDelivered(sinkID, intelID string) error {
+	_, err := s.db.Exec(`
+	UPDATE sink_queue
+	SET delivered = ?, attempts = attempts + 1, last_error = ''
+	WHERE sink_id = ? AND intel_id = ?`, time.Now().UTC(), sinkID, intelID)
+	if err != nil {
+		return fmt.Errorf("failed to mark sink delivery as done: %v", err)
+	}
+	return nil
+}
+
+// MarkSinkFailed records a failed delivery attempt. The delivery is retried
+// at retryAt, or given up if retryAt is zero.
+func (s *Store) MarkSinkFailed(sinkID, intelID string, retryAt time.Time, reason string) error {
+	var err error
+	if retryAt.IsZero() {
+		_, err = s.db.Exec(`
+		UPDATE sink_queue
+		SET failed = ?, attempts = attempts + 1, last_error = ?
+		WHERE sink_id = ? AND intel_id = ?`, time.Now().UTC(), reason, sinkID, intelID)
+	} else {
+		_, err = s.db.Exec(`
+		UPDATE sink_queue
+		SET next_attempt = ?, attempts = attempts + 1, last_error = ?
+		WHERE sink_id = ? AND intel_id = ?`, retryAt.UTC(), reason, sinkID, intelID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record sink delivery failure: %v", err)
+	}
+	return nil
+}
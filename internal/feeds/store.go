@@ -3,20 +3,28 @@ package feeds
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/NullMeDev/Infopulse-Node/internal/logger"
 	"github.com/NullMeDev/Infopulse-Node/internal/models"
+	"github.com/NullMeDev/Infopulse-Node/internal/subscriptions"
+	"github.com/NullMeDev/Infopulse-Node/internal/watchlist"
+	"github.com/NullMeDev/Infopulse-Node/pkg/utils"
 	_ "github.com/mattn/go-sqlite3" // SQLite driver
 )
 
 // Store handles persistence of intelligence data
 type Store struct {
-	db     *sql.DB
-	logger *logger.Logger
+	db       *sql.DB
+	logger   *logger.Logger
+	fullText bool // Whether the FTS5 search index is available
+
+	sinkRoutes []SinkRoute // Outbound sinks new items are queued for
 }
 
 // NewStore creates a new store instance
@@ -76,6 +84,31 @@ func (s *Store) initialize() error {
 		return fmt.Errorf("failed to create intelligence table: %v", err)
 	}
 
+	// Add columns introduced after the initial schema
+	columns := []struct {
+		name       string
+		definition string
+	}{
+		{"cve_id", "TEXT NOT NULL DEFAULT ''"},
+		{"cvss_score", "REAL NOT NULL DEFAULT 0"},
+		{"cvss_vector", "TEXT NOT NULL DEFAULT ''"},
+		{"cpes", "TEXT NOT NULL DEFAULT ''"},
+		{"exploited", "INTEGER NOT NULL DEFAULT 0"},
+		{"aliases", "TEXT NOT NULL DEFAULT ''"},
+		{"affected", "TEXT NOT NULL DEFAULT ''"},
+		{"score", "REAL NOT NULL DEFAULT 0"},
+		{"cluster_id", "TEXT NOT NULL DEFAULT ''"},
+		{"version", "TEXT NOT NULL DEFAULT ''"},
+		{"prerelease", "INTEGER NOT NULL DEFAULT 0"},
+		{"modified", "TIMESTAMP"},
+		{"backfill", "INTEGER NOT NULL DEFAULT 0"},
+	}
+	for _, column := range columns {
+		if err := s.addColumn("intelligence", column.name, column.definition); err != nil {
+			return err
+		}
+	}
+
 	// Create indices
 	_, err = s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_intelligence_hash ON intelligence(hash)`)
 	if err != nil {
@@ -92,10 +125,330 @@ func (s *Store) initialize() error {
 		return fmt.Errorf("failed to create published index: %v", err)
 	}
 
+	_, err = s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_intelligence_cve ON intelligence(cve_id)`)
+	if err != nil {
+		return fmt.Errorf("failed to create CVE index: %v", err)
+	}
+
+	_, err = s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_intelligence_cluster ON intelligence(cluster_id)`)
+	if err != nil {
+		return fmt.Errorf("failed to create cluster index: %v", err)
+	}
+
+	// Create autopost queue table. A row is queued when an item is first
+	// inserted and is marked posted once it has been delivered, so the
+	// queue survives restarts and no item is posted twice.
+	_, err = s.db.Exec(`
+	CREATE TABLE IF NOT EXISTS autopost_queue (
+		intel_id TEXT PRIMARY KEY,
+		queued TIMESTAMP NOT NULL,
+		posted TIMESTAMP,
+		channel_id TEXT
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create autopost queue table: %v", err)
+	}
+
+	_, err = s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_autopost_queue_posted ON autopost_queue(posted)`)
+	if err != nil {
+		return fmt.Errorf("failed to create autopost queue index: %v", err)
+	}
+
+	// Create fetch state table
+	_, err = s.db.Exec(`
+	CREATE TABLE IF NOT EXISTS fetch_state (
+		source_id TEXT PRIMARY KEY,
+		etag TEXT,
+		last_modified TEXT,
+		last_status INTEGER,
+		last_fetched TIMESTAMP,
+		last_bytes INTEGER,
+		last_error TEXT
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create fetch state table: %v", err)
+	}
+
+	// Create table of local files already processed by each source
+	_, err = s.db.Exec(`
+	CREATE TABLE IF NOT EXISTS processed_files (
+		source_id TEXT NOT NULL,
+		hash TEXT NOT NULL,
+		processed TIMESTAMP NOT NULL,
+		PRIMARY KEY (source_id, hash)
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create processed files table: %v", err)
+	}
+
+	// Create table of categories assigned to each item, backfilling
+	// primary categories of items stored before the table existed
+	var categoriesExist int
+	err = s.db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'intel_categories'`).Scan(&categoriesExist)
+	if err != nil {
+		return fmt.Errorf("failed to inspect schema: %v", err)
+	}
+
+	_, err = s.db.Exec(`
+	CREATE TABLE IF NOT EXISTS intel_categories (
+		intel_id TEXT NOT NULL,
+		category TEXT NOT NULL,
+		confidence REAL NOT NULL DEFAULT 0,
+		PRIMARY KEY (intel_id, category)
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create categories table: %v", err)
+	}
+
+	_, err = s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_intel_categories_category ON intel_categories(category)`)
+	if err != nil {
+		return fmt.Errorf("failed to create categories index: %v", err)
+	}
+
+	if categoriesExist == 0 {
+		_, err = s.db.Exec(`
+		INSERT OR IGNORE INTO intel_categories (intel_id, category, confidence)
+		SELECT id, category, 1 FROM intelligence`)
+		if err != nil {
+			return fmt.Errorf("failed to backfill categories: %v", err)
+		}
+	}
+
+	// Create table of CVEs mentioned by each item
+	var mentionsExist int
+	err = s.db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'intel_cves'`).Scan(&mentionsExist)
+	if err != nil {
+		return fmt.Errorf("failed to inspect schema: %v", err)
+	}
+
+	_, err = s.db.Exec(`
+	CREATE TABLE IF NOT EXISTS intel_cves (
+		intel_id TEXT NOT NULL,
+		cve_id TEXT NOT NULL,
+		PRIMARY KEY (intel_id, cve_id)
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create CVE mentions table: %v", err)
+	}
+
+	_, err = s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_intel_cves_cve ON intel_cves(cve_id)`)
+	if err != nil {
+		return fmt.Errorf("failed to create CVE mentions index: %v", err)
+	}
+
+	// Create table of CVEs known to be exploited
+	_, err = s.db.Exec(`
+	CREATE TABLE IF NOT EXISTS known_exploited (
+		cve_id TEXT PRIMARY KEY,
+		source_id TEXT NOT NULL,
+		added TIMESTAMP NOT NULL
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create known exploited table: %v", err)
+	}
+
+	// Create package watchlist table
+	_, err = s.db.Exec(`
+	CREATE TABLE IF NOT EXISTS watchlist (
+		ecosystem TEXT NOT NULL,
+		name TEXT NOT NULL,
+		version TEXT NOT NULL DEFAULT '',
+		added_by TEXT NOT NULL DEFAULT '',
+		added TIMESTAMP NOT NULL,
+		PRIMARY KEY (ecosystem, name, version)
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create watchlist table: %v", err)
+	}
+
+	// Create watchlist alert queue table
+	_, err = s.db.Exec(`
+	CREATE TABLE IF NOT EXISTS watch_alerts (
+		intel_id TEXT PRIMARY KEY,
+		packages TEXT NOT NULL,
+		queued TIMESTAMP NOT NULL,
+		posted TIMESTAMP,
+		channel_id TEXT
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create watch alerts table: %v", err)
+	}
+
+	// Index CVE mentions of items stored before the table existed
+	if mentionsExist == 0 {
+		if err := s.backfillCVEMentions(); err != nil {
+			return err
+		}
+	}
+
+	// Create full-text search index
+	if err := s.initSearch(); err != nil {
+		return err
+	}
+
+	// Create bookmark and read state tables
+	if err := s.initBookmarks(); err != nil {
+		return err
+	}
+
+	// Create subscription tables
+	if err := s.initSubscriptions(); err != nil {
+		return err
+	}
+
+	// Create outbound sink queue
+	if err := s.initSinks(); err != nil {
+		return err
+	}
+
 	s.logger.Info("Store", "Database initialized")
 	return nil
 }
 
+// backfillCVEMentions records the CVEs mentioned by all stored items
+func (s *Store) backfillCVEMentions() error {
+	rows, err := s.db.Query(`SELECT id, title, summary, cve_id FROM intelligence`)
+	if err != nil {
+		return fmt.Errorf("failed to query intelligence: %v", err)
+	}
+
+	mentions := make(map[string][]string)
+	for rows.Next() {
+		var id, title, summary, cveID string
+		var nullSummary sql.NullString
+		if err := rows.Scan(&id, &title, &nullSummary, &cveID); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan row: %v", err)
+		}
+		summary = nullSummary.String
+		if cves := utils.ExtractCVEs(cveID, title, summary); len(cves) > 0 {
+			mentions[id] = cves
+		}
+	}
+	rows.Close()
+
+	if len(mentions) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	for id, cves := range mentions {
+		for _, cve := range cves {
+			if _, err := tx.Exec(`INSERT OR IGNORE INTO intel_cves (intel_id, cve_id) VALUES (?, ?)`, id, cve); err != nil {
+				return fmt.Errorf("failed to record CVE mention: %v", err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	s.logger.Info("Store", fmt.Sprintf("Indexed CVE mentions for %d existing items", len(mentions)))
+	return nil
+}
+
+// intelligenceColumns lists the intelligence columns in scan order
+const intelligenceColumns = `id, source_id, category, title, url, summary, published, retrieved, hash, severity,
+	cve_id, cvss_score, cvss_vector, cpes, exploited, aliases, affected, score, cluster_id,
+	version, prerelease, modified, backfill`
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// addColumn adds a column to a table unless it already exists
+func (s *Store) addColumn(table, column, definition string) error {
+	rows, err := s.db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return fmt.Errorf("failed to inspect %s table: %v", table, err)
+	}
+
+	exists := false
+	for rows.Next() {
+		var (
+			cid        int
+			name       string
+			colType    string
+			notNull    int
+			defaultVal sql.NullString
+			primaryKey int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultVal, &primaryKey); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to inspect %s table: %v", table, err)
+		}
+		if name == column {
+			exists = true
+		}
+	}
+	rows.Close()
+
+	if exists {
+		return nil
+	}
+
+	_, err = s.db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+	if err != nil {
+		return fmt.Errorf("failed to add %s column: %v", column, err)
+	}
+	return nil
+}
+
+// encodeList encodes a string list for storage in a TEXT column
+func encodeList(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// decodeList decodes a string list stored by encodeList
+func decodeList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	var values []string
+	if err := json.Unmarshal([]byte(value), &values); err != nil {
+		return nil
+	}
+	return values
+}
+
+// encodeAffected encodes affected packages for storage in a TEXT column
+func encodeAffected(affected []models.AffectedPackage) string {
+	if len(affected) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(affected)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// decodeAffected decodes affected packages stored by encodeAffected
+func decodeAffected(value string) []models.AffectedPackage {
+	if value == "" {
+		return nil
+	}
+	var affected []models.AffectedPackage
+	if err := json.Unmarshal([]byte(value), &affected); err != nil {
+		return nil
+	}
+	return affected
+}
+
 // SaveIntelligence saves intelligence items to the database
 func (s *Store) SaveIntelligence(items []*models.Intelligence) (int, error) {
 	if len(items) == 0 {
@@ -112,17 +465,191 @@ func (s *Store) SaveIntelligence(items []*models.Intelligence) (int, error) {
 	// Prepare statement
 	stmt, err := tx.Prepare(`
 	INSERT OR IGNORE INTO intelligence 
-	(id, source_id, category, title, url, summary, published, retrieved, hash, severity)
-	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	(` + intelligenceColumns + `)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
 	if err != nil {
 		return 0, fmt.Errorf("failed to prepare statement: %v", err)
 	}
 	defer stmt.Close()
 
+	// Replace stored records that the source has revised since
+	updateStmt, err := tx.Prepare(`
+	UPDATE intelligence
+	SET title = ?, summary = ?, hash = ?, severity = ?, cve_id = ?, cvss_score = ?, cvss_vector = ?,
+		cpes = ?, aliases = ?, affected = ?, score = ?, modified = ?
+	WHERE id = ? AND (modified IS NULL OR modified < ?)`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare update statement: %v", err)
+	}
+	defer updateStmt.Close()
+
+	queueStmt, err := tx.Prepare(`
+	INSERT OR IGNORE INTO autopost_queue (intel_id, queued)
+	VALUES (?, ?)`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare queue statement: %v", err)
+	}
+	defer queueStmt.Close()
+
+	categoryStmt, err := tx.Prepare(`
+	INSERT OR IGNORE INTO intel_categories (intel_id, category, confidence)
+	VALUES (?, ?, ?)`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare category statement: %v", err)
+	}
+	defer categoryStmt.Close()
+
+	mentionStmt, err := tx.Prepare(`
+	INSERT OR IGNORE INTO intel_cves (intel_id, cve_id)
+	VALUES (?, ?)`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare mention statement: %v", err)
+	}
+	defer mentionStmt.Close()
+
+	exploitedStmt, err := tx.Prepare(`
+	INSERT OR IGNORE INTO known_exploited (cve_id, source_id, added)
+	VALUES (?, ?, ?)`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare exploited statement: %v", err)
+	}
+	defer exploitedStmt.Close()
+
+	// Index new items for search if FTS5 is available
+	var searchStmt, unindexStmt *sql.Stmt
+	if s.fullText {
+		searchStmt, err = tx.Prepare(`
+		INSERT INTO intel_search (id, title, summary)
+		VALUES (?, ?, ?)`)
+		if err != nil {
+			return 0, fmt.Errorf("failed to prepare search statement: %v", err)
+		}
+		defer searchStmt.Close()
+
+		unindexStmt, err = tx.Prepare(`DELETE FROM intel_search WHERE id = ?`)
+		if err != nil {
+			return 0, fmt.Errorf("failed to prepare search statement: %v", err)
+		}
+		defer unindexStmt.Close()
+	}
+
+	alertStmt, err := tx.Prepare(`
+	INSERT OR IGNORE INTO watch_alerts (intel_id, packages, queued)
+	VALUES (?, ?, ?)`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare alert statement: %v", err)
+	}
+	defer alertStmt.Close()
+
+	// Revised advisories are alerted again if they affect more watched
+	// packages than their last alert named
+	alertedStmt, err := tx.Prepare(`SELECT packages FROM watch_alerts WHERE intel_id = ?`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare alert statement: %v", err)
+	}
+	defer alertedStmt.Close()
+
+	realertStmt, err := tx.Prepare(`
+	INSERT OR REPLACE INTO watch_alerts (intel_id, packages, queued)
+	VALUES (?, ?, ?)`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare alert statement: %v", err)
+	}
+	defer realertStmt.Close()
+
+	deliveryStmt, err := tx.Prepare(`
+	INSERT OR IGNORE INTO subscription_queue (subscription_id, intel_id, queued)
+	VALUES (?, ?, ?)`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare delivery statement: %v", err)
+	}
+	defer deliveryStmt.Close()
+
+	// A story is delivered to a subscription once, through whichever of its
+	// reports matched first
+	deliveredStmt, err := tx.Prepare(`
+	SELECT COUNT(*) FROM subscription_queue q
+	JOIN intelligence i ON i.id = q.intel_id
+	WHERE q.subscription_id = ? AND (i.id = ? OR i.cluster_id = ?)`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare delivered statement: %v", err)
+	}
+	defer deliveredStmt.Close()
+
+	sinkStmt, err := tx.Prepare(`
+	INSERT OR IGNORE INTO sink_queue (sink_id, intel_id, queued, next_attempt)
+	VALUES (?, ?, ?, ?)`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare sink statement: %v", err)
+	}
+	defer sinkStmt.Close()
+
+	clusterStmt, err := tx.Prepare(`SELECT cluster_id, backfill FROM intelligence WHERE id = ?`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare cluster statement: %v", err)
+	}
+	defer clusterStmt.Close()
+
+	subscribed, err := querySubscriptions(tx)
+	if err != nil {
+		return 0, err
+	}
+
+	// Load the watchlist only if some item is an advisory
+	var watched []models.WatchedPackage
+	for _, item := range items {
+		if len(item.Affected) > 0 {
+			watched, err = queryWatchlist(tx)
+			if err != nil {
+				return 0, err
+			}
+			break
+		}
+	}
+
+	now := time.Now().UTC()
+
+	// queueSubscriptions queues an item for matching subscriptions, unless
+	// another report of its story was already queued for the subscription.
+	// A later report or revision may match a filter that the first one
+	// didn't.
+	queueSubscriptions := func(item *models.Intelligence, story string) {
+		for _, sub := range subscriptions.Matching(item, subscribed) {
+			var queued int
+			if err := deliveredStmt.QueryRow(sub.ID, story, story).Scan(&queued); err != nil {
+				s.logger.Error("Store", fmt.Sprintf("Failed to check deliveries of subscription %d: %v", sub.ID, err))
+				continue
+			}
+			if queued > 0 {
+				continue
+			}
+			if _, err := deliveryStmt.Exec(sub.ID, item.ID, now); err != nil {
+				s.logger.Error("Store", fmt.Sprintf("Failed to queue item for subscription %d: %v", sub.ID, err))
+			}
+		}
+	}
+
+	// queueSinks queues an item for the sinks whose routes it matches, or
+	// only for filtered routes when a revised record is matched again.
+	// Items already queued for a sink are not queued twice.
+	queueSinks := func(item *models.Intelligence, filteredOnly bool) {
+		for _, route := range s.sinkRoutes {
+			if filteredOnly && subscriptions.Empty(route.Filter) {
+				continue
+			}
+			if !route.Matches(item) {
+				continue
+			}
+			if _, err := sinkStmt.Exec(route.ID, item.ID, now, now); err != nil {
+				s.logger.Error("Store", fmt.Sprintf("Failed to queue item for sink %s: %v", route.ID, err))
+			}
+		}
+	}
+
 	// Insert items
-	count := 0
+	count, updated := 0, 0
 	for _, item := range items {
-		_, err := stmt.Exec(
+		result, err := stmt.Exec(
 			item.ID,
 			item.SourceID,
 			item.Category,
@@ -133,95 +660,585 @@ func (s *Store) SaveIntelligence(items []*models.Intelligence) (int, error) {
 			item.Retrieved,
 			item.Hash,
 			item.Severity,
+			item.CVEID,
+			item.CVSSScore,
+			item.CVSSVector,
+			encodeList(item.CPEs),
+			item.Exploited,
+			encodeList(item.Aliases),
+			encodeAffected(item.Affected),
+			item.Score,
+			item.ClusterID,
+			item.Version,
+			item.Prerelease,
+			item.Modified,
+			item.Backfill,
 		)
 		if err != nil {
 			s.logger.Error("Store", fmt.Sprintf("Failed to insert item: %v", err))
 			continue
 		}
+
+		// Skip items that were already stored, unless the source has
+		// revised the record since, e.g. to add CVSS scores or CPEs
+		if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+			if item.Modified.IsZero() {
+				continue
+			}
+
+			result, err := updateStmt.Exec(
+				item.Title,
+				item.Summary,
+				item.Hash,
+				item.Severity,
+				item.CVEID,
+				item.CVSSScore,
+				item.CVSSVector,
+				encodeList(item.CPEs),
+				encodeList(item.Aliases),
+				encodeAffected(item.Affected),
+				item.Score,
+				item.Modified,
+				item.ID,
+				item.Modified,
+			)
+			if err != nil {
+				s.logger.Error("Store", fmt.Sprintf("Failed to update item: %v", err))
+				continue
+			}
+			if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+				continue
+			}
+
+			if unindexStmt != nil {
+				if _, err := unindexStmt.Exec(item.ID); err != nil {
+					s.logger.Error("Store", fmt.Sprintf("Failed to index item for search: %v", err))
+				} else if _, err := searchStmt.Exec(item.ID, item.Title, item.Summary); err != nil {
+					s.logger.Error("Store", fmt.Sprintf("Failed to index item for search: %v", err))
+				}
+			}
+			for _, cve := range utils.ExtractCVEs(item.CVEID, item.Title, item.Summary, strings.Join(item.Aliases, " ")) {
+				if _, err := mentionStmt.Exec(item.ID, cve); err != nil {
+					s.logger.Error("Store", fmt.Sprintf("Failed to record CVE mention: %v", err))
+				}
+			}
+
+			// Revisions add the severity and vendors that subscription and
+			// sink filters match on. Backfilled items stay undelivered.
+			var cluster string
+			var backfill bool
+			if err := clusterStmt.QueryRow(item.ID).Scan(&cluster, &backfill); err != nil {
+				s.logger.Error("Store", fmt.Sprintf("Failed to look up story of %s: %v", item.ID, err))
+			} else if !backfill && cluster != "" {
+				queueSubscriptions(item, cluster)
+			} else if !backfill {
+				queueSubscriptions(item, item.ID)
+				queueSinks(item, true)
+			}
+
+			// Match the revised affected packages against the watchlist
+			if names := watchedNames(item, watched); len(names) > 0 {
+				var previous string
+				err := alertedStmt.QueryRow(item.ID).Scan(&previous)
+				if err != nil && err != sql.ErrNoRows {
+					s.logger.Error("Store", fmt.Sprintf("Failed to check watchlist alert: %v", err))
+				} else if added := newNames(names, decodeList(previous)); len(added) > 0 {
+					if _, err := realertStmt.Exec(item.ID, encodeList(names), now); err != nil {
+						s.logger.Error("Store", fmt.Sprintf("Failed to queue watchlist alert: %v", err))
+					} else {
+						s.logger.Warning("Store", fmt.Sprintf("Revised advisory %s affects watched packages: %s", item.ID, strings.Join(added, ", ")))
+					}
+				}
+			}
+
+			updated++
+			continue
+		}
+
+		// Queue new item for matching subscriptions
+		story := item.ClusterID
+		if story == "" {
+			story = item.ID
+		}
+		if !item.Backfill {
+			queueSubscriptions(item, story)
+		}
+
+		// Queue new item for autoposting and sinks, unless it is another
+		// report of a story that is already queued or posted
+		if item.ClusterID == "" && !item.Backfill {
+			if _, err := queueStmt.Exec(item.ID, now); err != nil {
+				s.logger.Error("Store", fmt.Sprintf("Failed to queue item for autopost: %v", err))
+			}
+			queueSinks(item, false)
+		}
+
+		if searchStmt != nil {
+			if _, err := searchStmt.Exec(item.ID, item.Title, item.Summary); err != nil {
+				s.logger.Error("Store", fmt.Sprintf("Failed to index item for search: %v", err))
+			}
+		}
+
+		// Record all assigned categories, including the primary one. A
+		// primary category that wasn't scored was set by the source, so it
+		// is recorded with full confidence.
+		confidence := 1.0
+		for _, category := range item.Categories {
+			if category.Category == item.Category {
+				confidence = category.Confidence
+				break
+			}
+		}
+		if _, err := categoryStmt.Exec(item.ID, item.Category, confidence); err != nil {
+			s.logger.Error("Store", fmt.Sprintf("Failed to record category: %v", err))
+		}
+		for _, category := range item.Categories {
+			if category.Category == item.Category {
+				continue
+			}
+			if _, err := categoryStmt.Exec(item.ID, category.Category, category.Confidence); err != nil {
+				s.logger.Error("Store", fmt.Sprintf("Failed to record category: %v", err))
+			}
+		}
+
+		// Queue an alert if the advisory affects watched packages
+		if names := watchedNames(item, watched); len(names) > 0 {
+			if _, err := alertStmt.Exec(item.ID, encodeList(names), now); err != nil {
+				s.logger.Error("Store", fmt.Sprintf("Failed to queue watchlist alert: %v", err))
+			} else {
+				s.logger.Warning("Store", fmt.Sprintf("Advisory %s affects watched packages: %s", item.ID, strings.Join(names, ", ")))
+			}
+		}
+
+		// Record mentioned CVEs
+		for _, cve := range utils.ExtractCVEs(item.CVEID, item.Title, item.Summary, strings.Join(item.Aliases, " ")) {
+			if _, err := mentionStmt.Exec(item.ID, cve); err != nil {
+				s.logger.Error("Store", fmt.Sprintf("Failed to record CVE mention: %v", err))
+			}
+		}
 		count++
 	}
 
+	// Record CVEs that sources list as exploited, including already stored
+	// entries so the catalog stays complete
+	for _, item := range items {
+		if !item.Exploited || item.CVEID == "" {
+			continue
+		}
+		if _, err := exploitedStmt.Exec(item.CVEID, item.SourceID, now); err != nil {
+			s.logger.Error("Store", fmt.Sprintf("Failed to record exploited CVE: %v", err))
+		}
+	}
+
+	// Flag every item that mentions an exploited CVE
+	result, err := tx.Exec(`
+	UPDATE intelligence SET exploited = 1
+	WHERE exploited = 0 AND id IN (
+		SELECT m.intel_id FROM intel_cves m
+		JOIN known_exploited k ON k.cve_id = m.cve_id
+	)`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to flag exploited items: %v", err)
+	}
+	if flagged, err := result.RowsAffected(); err == nil && flagged > 0 {
+		s.logger.Info("Store", fmt.Sprintf("Flagged %d items as actively exploited", flagged))
+	}
+
 	// Commit transaction
 	if err := tx.Commit(); err != nil {
 		return 0, fmt.Errorf("failed to commit transaction: %v", err)
 	}
 
 	s.logger.Info("Store", fmt.Sprintf("Inserted %d intelligence items", count))
+	if updated > 0 {
+		s.logger.Info("Store", fmt.Sprintf("Updated %d revised intelligence items", updated))
+	}
 	return count, nil
 }
 
-// GetIntelligenceByID retrieves an intelligence item by ID
-func (s *Store) GetIntelligenceByID(id string) (*models.Intelligence, error) {
-	row := s.db.QueryRow(`
-	SELECT id, source_id, category, title, url, summary, published, retrieved, hash, severity
-	FROM intelligence
-	WHERE id = ?`, id)
-
-	item := &models.Intelligence{}
-	err := row.Scan(
-		&item.ID,
-		&item.SourceID,
-		&item.Category,
-		&item.Title,
-		&item.URL,
-		&item.Summary,
-		&item.Published,
-		&item.Retrieved,
-		&item.Hash,
-		&item.Severity,
-	)
+// RecordMentions records CVEs mentioned about stored items, by item ID.
+// IDs of items that aren't stored are ignored. Items without a CVE ID take
+// the first CVE, and items mentioning exploited CVEs are flagged.
+func (s *Store) RecordMentions(mentions map[string][]string) error {
+	tx, err := s.db.Begin()
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil // No item found
-		}
-		return nil, fmt.Errorf("failed to query intelligence: %v", err)
+		return fmt.Errorf("failed to begin transaction: %v", err)
 	}
+	defer tx.Rollback()
 
+	for id, cves := range mentions {
+		for _, cve := range cves {
+			_, err := tx.Exec(`
+			INSERT OR IGNORE INTO intel_cves (intel_id, cve_id)
+			SELECT id, ? FROM intelligence WHERE id = ?`, cve, id)
+			if err != nil {
+				return fmt.Errorf("failed to record CVE mention: %v", err)
+			}
+		}
+		if len(cves) > 0 {
+			_, err := tx.Exec(`UPDATE intelligence SET cve_id = ? WHERE id = ? AND cve_id = ''`, cves[0], id)
+			if err != nil {
+				return fmt.Errorf("failed to set CVE ID: %v", err)
+			}
+		}
+	}
+
+	_, err = tx.Exec(`
+	UPDATE intelligence SET exploited = 1
+	WHERE exploited = 0 AND id IN (
+		SELECT m.intel_id FROM intel_cves m
+		JOIN known_exploited k ON k.cve_id = m.cve_id
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to flag exploited items: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return nil
+}
+
+// watchedNames returns the watched packages an advisory affects
+func watchedNames(item *models.Intelligence, watched []models.WatchedPackage) []string {
+	var names []string
+	for _, pkg := range watchlist.Matches(item, watched) {
+		names = append(names, watchlist.Format(pkg))
+	}
+	return names
+}
+
+// newNames returns the names that are not in previous
+func newNames(names, previous []string) []string {
+	seen := make(map[string]bool, len(previous))
+	for _, name := range previous {
+		seen[name] = true
+	}
+
+	var added []string
+	for _, name := range names {
+		if !seen[name] {
+			added = append(added, name)
+		}
+	}
+	return added
+}
+
+// GetIntelligenceByID retrieves an intelligence item by ID
+func (s *Store) GetIntelligenceByID(id string) (*models.Intelligence, error) {
+	row := s.db.QueryRow(`
+	SELECT `+intelligenceColumns+`
+	FROM intelligence
+	WHERE id = ?`, id)
+
+	item, err := scanItem(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // No item found
+		}
+		return nil, fmt.Errorf("failed to query intelligence: %v", err)
+	}
+
+	if err := s.loadRelated([]*models.Intelligence{item}); err != nil {
+		return nil, err
+	}
 	return item, nil
 }
 
+// FindIntelligenceIDs returns the IDs of up to limit items whose ID starts
+// with a prefix
+func (s *Store) FindIntelligenceIDs(prefix string, limit int) ([]string, error) {
+	rows, err := s.db.Query(`
+	SELECT id FROM intelligence
+	WHERE id LIKE ? ESCAPE '\'
+	ORDER BY id LIMIT ?`, likeEscaper.Replace(prefix)+"%", limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find intelligence: %v", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan intelligence ID: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// GetRelatedIntelligence retrieves items related to an item: the other
+// reports of its story and items mentioning the same CVEs. The item's own
+// alternates are left out since they are already loaded with it.
+func (s *Store) GetRelatedIntelligence(item *models.Intelligence, limit int) ([]*models.Intelligence, error) {
+	rows, err := s.db.Query(`
+	SELECT `+intelligenceColumns+`
+	FROM intelligence
+	WHERE id != ? AND cluster_id != ? AND (
+		(? != '' AND (id = ? OR cluster_id = ?))
+		OR id IN (
+			SELECT m.intel_id FROM intel_cves m
+			WHERE m.cve_id IN (SELECT cve_id FROM intel_cves WHERE intel_id = ?)
+		)
+	)
+	ORDER BY published DESC LIMIT ?`,
+		item.ID, item.ID, item.ClusterID, item.ClusterID, item.ClusterID, item.ID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query related intelligence: %v", err)
+	}
+	items := s.scanIntelligence(rows)
+	rows.Close()
+
+	return items, nil
+}
+
+// IntelFilter restricts which intelligence items a query returns
+type IntelFilter struct {
+	Category      models.Category // Only items in this category (empty for all)
+	SourceID      string          // Only items from this source (empty for all)
+	ExploitedOnly bool            // Only items flagged as actively exploited
+	Since         time.Time       // Only items published at or after this time (zero for all)
+	After         *Cursor         // Only items after this position, for paging (nil for the first page)
+	UnreadBy      string          // Only items this user has not marked as read (empty for all)
+}
+
+// Cursor is a position in newest-first results. Pages continue from the
+// last item shown rather than an offset, so items arriving while a user
+// pages through results do not shift them.
+type Cursor struct {
+	Published time.Time
+	ID        string
+}
+
+// CursorAfter returns the position following an item
+func CursorAfter(item *models.Intelligence) *Cursor {
+	return &Cursor{Published: item.Published, ID: item.ID}
+}
+
 // GetLatestIntelligence retrieves the latest intelligence items
 func (s *Store) GetLatestIntelligence(category models.Category, limit int) ([]*models.Intelligence, error) {
-	var rows *sql.Rows
-	var err error
-
-	if category == "" {
-		// Query all categories
-		rows, err = s.db.Query(`
-		SELECT id, source_id, category, title, url, summary, published, retrieved, hash, severity
-		FROM intelligence
-		ORDER BY published DESC
-		LIMIT ?`, limit)
-	} else {
-		// Query specific category
-		rows, err = s.db.Query(`
-		SELECT id, source_id, category, title, url, summary, published, retrieved, hash, severity
-		FROM intelligence
-		WHERE category = ?
-		ORDER BY published DESC
-		LIMIT ?`, category, limit)
-	}
+	return s.QueryIntelligence(IntelFilter{Category: category}, limit)
+}
+
+// QueryIntelligence retrieves the latest intelligence items matching a filter
+func (s *Store) QueryIntelligence(filter IntelFilter, limit int) ([]*models.Intelligence, error) {
+	conditions, args := filterConditions(filter)
 
+	query := `SELECT ` + intelligenceColumns + ` FROM intelligence
+	WHERE ` + strings.Join(conditions, " AND ") + `
+	ORDER BY published DESC, id DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := s.db.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query intelligence: %v", err)
 	}
+	items := s.scanIntelligence(rows)
+	rows.Close()
+
+	if err := s.loadRelated(items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// GetRecentIntelligence retrieves every item retrieved since a time,
+// including alternate reports of stories
+func (s *Store) GetRecentIntelligence(since time.Time) ([]*models.Intelligence, error) {
+	rows, err := s.db.Query(`
+	SELECT `+intelligenceColumns+`
+	FROM intelligence
+	WHERE retrieved >= ?
+	ORDER BY retrieved ASC`, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recent intelligence: %v", err)
+	}
+	defer rows.Close()
+
+	return s.scanIntelligence(rows), nil
+}
+
+// filterConditions builds the WHERE conditions of a filter. Each story is
+// shown once, through its canonical item.
+func filterConditions(filter IntelFilter) ([]string, []interface{}) {
+	conditions := []string{"cluster_id = ''"}
+	var args []interface{}
+
+	if filter.Category != "" {
+		conditions = append(conditions, "id IN (SELECT intel_id FROM intel_categories WHERE category = ?)")
+		args = append(args, filter.Category)
+	}
+	if filter.SourceID != "" {
+		conditions = append(conditions, "source_id = ?")
+		args = append(args, filter.SourceID)
+	}
+	if filter.ExploitedOnly {
+		conditions = append(conditions, "exploited = 1")
+	}
+	if !filter.Since.IsZero() {
+		conditions = append(conditions, "published >= ?")
+		args = append(args, filter.Since)
+	}
+	if filter.After != nil {
+		conditions = append(conditions, "(published < ? OR (published = ? AND id < ?))")
+		args = append(args, filter.After.Published, filter.After.Published, filter.After.ID)
+	}
+	if filter.UnreadBy != "" {
+		conditions = append(conditions, "id NOT IN (SELECT intel_id FROM read_items WHERE user_id = ?)")
+		args = append(args, filter.UnreadBy)
+	}
+
+	return conditions, args
+}
+
+// loadRelated fills in the categories and alternate reports of each item
+func (s *Store) loadRelated(items []*models.Intelligence) error {
+	if err := s.loadCategories(items); err != nil {
+		return err
+	}
+	return s.loadAlternates(items)
+}
+
+// loadAlternates fills in the other reports of each canonical item
+func (s *Store) loadAlternates(items []*models.Intelligence) error {
+	byID := make(map[string]*models.Intelligence, len(items))
+	placeholders := make([]string, 0, len(items))
+	args := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		item.Alternates = nil
+		if item.ClusterID != "" {
+			continue
+		}
+		byID[item.ID] = item
+		placeholders = append(placeholders, "?")
+		args = append(args, item.ID)
+	}
+	if len(placeholders) == 0 {
+		return nil
+	}
+
+	rows, err := s.db.Query(`
+	SELECT cluster_id, id, source_id, title, url
+	FROM intelligence
+	WHERE cluster_id IN (`+strings.Join(placeholders, ", ")+`)
+	ORDER BY published ASC`, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query alternates: %v", err)
+	}
 	defer rows.Close()
 
+	for rows.Next() {
+		var clusterID string
+		var alternate models.Alternate
+		if err := rows.Scan(&clusterID, &alternate.ID, &alternate.SourceID, &alternate.Title, &alternate.URL); err != nil {
+			return fmt.Errorf("failed to scan alternate: %v", err)
+		}
+		if item, ok := byID[clusterID]; ok {
+			item.Alternates = append(item.Alternates, alternate)
+		}
+	}
+
+	return nil
+}
+
+// loadCategories fills in the categories of each item, primary first
+func (s *Store) loadCategories(items []*models.Intelligence) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*models.Intelligence, len(items))
+	placeholders := make([]string, 0, len(items))
+	args := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+		item.Categories = nil
+		placeholders = append(placeholders, "?")
+		args = append(args, item.ID)
+	}
+
+	rows, err := s.db.Query(`
+	SELECT intel_id, category, confidence
+	FROM intel_categories
+	WHERE intel_id IN (`+strings.Join(placeholders, ", ")+`)
+	ORDER BY confidence DESC`, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query categories: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		var score models.CategoryScore
+		if err := rows.Scan(&id, &score.Category, &score.Confidence); err != nil {
+			return fmt.Errorf("failed to scan category: %v", err)
+		}
+		if item, ok := byID[id]; ok {
+			item.Categories = append(item.Categories, score)
+		}
+	}
+
+	// Keep the primary category first
+	for _, item := range items {
+		for i, score := range item.Categories {
+			if score.Category == item.Category && i > 0 {
+				copy(item.Categories[1:i+1], item.Categories[:i])
+				item.Categories[0] = score
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// scanItem reads a single intelligence item
+func scanItem(row rowScanner) (*models.Intelligence, error) {
+	item := &models.Intelligence{}
+	var cpes, aliases, affected string
+	var modified sql.NullTime
+	err := row.Scan(
+		&item.ID,
+		&item.SourceID,
+		&item.Category,
+		&item.Title,
+		&item.URL,
+		&item.Summary,
+		&item.Published,
+		&item.Retrieved,
+		&item.Hash,
+		&item.Severity,
+		&item.CVEID,
+		&item.CVSSScore,
+		&item.CVSSVector,
+		&cpes,
+		&item.Exploited,
+		&aliases,
+		&affected,
+		&item.Score,
+		&item.ClusterID,
+		&item.Version,
+		&item.Prerelease,
+		&modified,
+		&item.Backfill,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	item.CPEs = decodeList(cpes)
+	item.Aliases = decodeList(aliases)
+	item.Affected = decodeAffected(affected)
+	item.Modified = modified.Time
+	return item, nil
+}
+
+// scanIntelligence reads intelligence items from a result set
+func (s *Store) scanIntelligence(rows *sql.Rows) []*models.Intelligence {
 	var items []*models.Intelligence
 	for rows.Next() {
-		item := &models.Intelligence{}
-		err := rows.Scan(
-			&item.ID,
-			&item.SourceID,
-			&item.Category,
-			&item.Title,
-			&item.URL,
-			&item.Summary,
-			&item.Published,
-			&item.Retrieved,
-			&item.Hash,
-			&item.Severity,
-		)
+		item, err := scanItem(rows)
 		if err != nil {
 			s.logger.Error("Store", fmt.Sprintf("Failed to scan row: %v", err))
 			continue
@@ -229,9 +1246,60 @@ func (s *Store) GetLatestIntelligence(category models.Category, limit int) ([]*m
 		items = append(items, item)
 	}
 
+	return items
+}
+
+// GetPendingAutoposts retrieves queued items that have not been posted yet, oldest first
+func (s *Store) GetPendingAutoposts(limit int) ([]*models.Intelligence, error) {
+	rows, err := s.db.Query(`
+	SELECT `+intelligenceColumns+`
+	FROM intelligence
+	WHERE id IN (SELECT intel_id FROM autopost_queue WHERE posted IS NULL)
+	ORDER BY published ASC
+	LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query autopost queue: %v", err)
+	}
+	items := s.scanIntelligence(rows)
+	rows.Close()
+
+	if err := s.loadRelated(items); err != nil {
+		return nil, err
+	}
 	return items, nil
 }
 
+// MarkPosted records that an item has been delivered to a channel.
+// An empty channel ID marks the item as handled without posting it.
+func (s *Store) MarkPosted(id, channelID string) error {
+	_, err := s.db.Exec(`
+	UPDATE autopost_queue
+	SET posted = ?, channel_id = ?
+	WHERE intel_id = ?`, time.Now().UTC(), channelID, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark item as posted: %v", err)
+	}
+	return nil
+}
+
+// ExpireAutoposts marks queued items older than the cutoff as handled so a
+// long outage does not flood channels with stale items
+func (s *Store) ExpireAutoposts(before time.Time) (int, error) {
+	result, err := s.db.Exec(`
+	UPDATE autopost_queue
+	SET posted = ?, channel_id = ''
+	WHERE posted IS NULL AND queued < ?`, time.Now().UTC(), before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire autopost queue: %v", err)
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire autopost queue: %v", err)
+	}
+	return int(count), nil
+}
+
 // GetTotalCount gets the total count of intelligence items
 func (s *Store) GetTotalCount() (int, error) {
 	var count int
@@ -245,9 +1313,284 @@ func (s *Store) GetTotalCount() (int, error) {
 // GetCategoryCount gets the count of intelligence items by category
 func (s *Store) GetCategoryCount(category models.Category) (int, error) {
 	var count int
-	err := s.db.QueryRow("SELECT COUNT(*) FROM intelligence WHERE category = ?", category).Scan(&count)
+	err := s.db.QueryRow("SELECT COUNT(*) FROM intel_categories WHERE category = ?", category).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get count: %v", err)
 	}
 	return count, nil
 }
+
+// GetFetchState retrieves the fetch state of a source. An empty state is
+// returned for sources that have never been fetched.
+func (s *Store) GetFetchState(sourceID string) (*models.FetchState, error) {
+	state := &models.FetchState{SourceID: sourceID}
+
+	var lastFetched sql.NullTime
+	err := s.db.QueryRow(`
+	SELECT etag, last_modified, last_status, last_fetched, last_bytes, last_error
+	FROM fetch_state
+	WHERE source_id = ?`, sourceID).Scan(
+		&state.ETag,
+		&state.LastModified,
+		&state.LastStatus,
+		&lastFetched,
+		&state.LastBytes,
+		&state.LastError,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return state, nil
+		}
+		return nil, fmt.Errorf("failed to query fetch state: %v", err)
+	}
+
+	if lastFetched.Valid {
+		state.LastFetched = lastFetched.Time
+	}
+
+	// Load the files already processed by local file sources
+	rows, err := s.db.Query(`SELECT hash FROM processed_files WHERE source_id = ?`, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query processed files: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			s.logger.Error("Store", fmt.Sprintf("Failed to scan row: %v", err))
+			continue
+		}
+		if state.ProcessedFiles == nil {
+			state.ProcessedFiles = make(map[string]bool)
+		}
+		state.ProcessedFiles[hash] = true
+	}
+
+	return state, nil
+}
+
+// GetFetchStates retrieves the fetch state of all sources
+func (s *Store) GetFetchStates() ([]*models.FetchState, error) {
+	rows, err := s.db.Query(`
+	SELECT source_id, etag, last_modified, last_status, last_fetched, last_bytes, last_error
+	FROM fetch_state
+	ORDER BY source_id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query fetch state: %v", err)
+	}
+	defer rows.Close()
+
+	var states []*models.FetchState
+	for rows.Next() {
+		state := &models.FetchState{}
+		var lastFetched sql.NullTime
+		err := rows.Scan(
+			&state.SourceID,
+			&state.ETag,
+			&state.LastModified,
+			&state.LastStatus,
+			&lastFetched,
+			&state.LastBytes,
+			&state.LastError,
+		)
+		if err != nil {
+			s.logger.Error("Store", fmt.Sprintf("Failed to scan row: %v", err))
+			continue
+		}
+		if lastFetched.Valid {
+			state.LastFetched = lastFetched.Time
+		}
+		states = append(states, state)
+	}
+
+	return states, nil
+}
+
+// SaveFetchState saves the fetch state of a source, along with the files
+// it processed and those it no longer needs to remember
+func (s *Store) SaveFetchState(state *models.FetchState) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+	INSERT OR REPLACE INTO fetch_state
+	(source_id, etag, last_modified, last_status, last_fetched, last_bytes, last_error)
+	VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		state.SourceID,
+		state.ETag,
+		state.LastModified,
+		state.LastStatus,
+		state.LastFetched,
+		state.LastBytes,
+		state.LastError,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save fetch state: %v", err)
+	}
+
+	now := time.Now().UTC()
+	for _, hash := range state.NewFiles {
+		_, err := tx.Exec(`
+		INSERT OR IGNORE INTO processed_files (source_id, hash, processed)
+		VALUES (?, ?, ?)`, state.SourceID, hash, now)
+		if err != nil {
+			return fmt.Errorf("failed to record processed file: %v", err)
+		}
+	}
+	for _, hash := range state.StaleFiles {
+		_, err := tx.Exec(`
+		DELETE FROM processed_files WHERE source_id = ? AND hash = ?`, state.SourceID, hash)
+		if err != nil {
+			return fmt.Errorf("failed to forget processed file: %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit fetch state: %v", err)
+	}
+	return nil
+}
+
+// queryer is implemented by *sql.DB and *sql.Tx
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// queryWatchlist reads the package watchlist
+func queryWatchlist(q queryer) ([]models.WatchedPackage, error) {
+	rows, err := q.Query(`
+	SELECT ecosystem, name, version, added_by, added
+	FROM watchlist
+	ORDER BY ecosystem, name, version`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query watchlist: %v", err)
+	}
+	defer rows.Close()
+
+	var packages []models.WatchedPackage
+	for rows.Next() {
+		var pkg models.WatchedPackage
+		if err := rows.Scan(&pkg.Ecosystem, &pkg.Name, &pkg.Version, &pkg.AddedBy, &pkg.Added); err != nil {
+			return nil, fmt.Errorf("failed to scan watchlist: %v", err)
+		}
+		packages = append(packages, pkg)
+	}
+
+	return packages, nil
+}
+
+// GetWatchlist retrieves all watched packages
+func (s *Store) GetWatchlist() ([]models.WatchedPackage, error) {
+	return queryWatchlist(s.db)
+}
+
+// AddWatchedPackages adds packages to the watchlist, returning how many were new
+func (s *Store) AddWatchedPackages(packages []models.WatchedPackage) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+	INSERT OR IGNORE INTO watchlist (ecosystem, name, version, added_by, added)
+	VALUES (?, ?, ?, ?, ?)`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare statement: %v", err)
+	}
+	defer stmt.Close()
+
+	count := 0
+	now := time.Now().UTC()
+	for _, pkg := range packages {
+		result, err := stmt.Exec(pkg.Ecosystem, pkg.Name, pkg.Version, pkg.AddedBy, now)
+		if err != nil {
+			return 0, fmt.Errorf("failed to add watched package: %v", err)
+		}
+		if affected, err := result.RowsAffected(); err == nil && affected > 0 {
+			count++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return count, nil
+}
+
+// RemoveWatchedPackage removes all versions of a package from the watchlist
+func (s *Store) RemoveWatchedPackage(ecosystem, name string) (int, error) {
+	result, err := s.db.Exec(`
+	DELETE FROM watchlist
+	WHERE ecosystem = ? COLLATE NOCASE AND name = ?`, ecosystem, name)
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove watched package: %v", err)
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove watched package: %v", err)
+	}
+	return int(count), nil
+}
+
+// GetPendingWatchAlerts retrieves watchlist alerts that have not been posted yet
+func (s *Store) GetPendingWatchAlerts(limit int) ([]*models.WatchAlert, error) {
+	rows, err := s.db.Query(`
+	SELECT intel_id, packages
+	FROM watch_alerts
+	WHERE posted IS NULL
+	ORDER BY queued ASC
+	LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query watch alerts: %v", err)
+	}
+
+	type pending struct {
+		id       string
+		packages string
+	}
+	var queued []pending
+	for rows.Next() {
+		var p pending
+		if err := rows.Scan(&p.id, &p.packages); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan watch alert: %v", err)
+		}
+		queued = append(queued, p)
+	}
+	rows.Close()
+
+	var alerts []*models.WatchAlert
+	for _, p := range queued {
+		item, err := s.GetIntelligenceByID(p.id)
+		if err != nil {
+			return nil, err
+		}
+		if item == nil {
+			continue
+		}
+		alerts = append(alerts, &models.WatchAlert{
+			Item:     item,
+			Packages: decodeList(p.packages),
+		})
+	}
+
+	return alerts, nil
+}
+
+// MarkWatchAlertPosted records that a watchlist alert has been delivered
+func (s *Store) MarkWatchAlertPosted(id, channelID string) error {
+	_, err := s.db.Exec(`
+	UPDATE watch_alerts
+	SET posted = ?, channel_id = ?
+	WHERE intel_id = ?`, time.Now().UTC(), channelID, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark watch alert as posted: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+// internal/feeds/subscriptions.go
+package feeds
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+)
+
+// maxDeliveryAttempts is how often delivery of an item to a subscription is
+// tried before it is given up, e.g. because the user does not accept DMs
+const maxDeliveryAttempts = 5
+
+// initSubscriptions creates the subscription and delivery queue tables
+func (s *Store) initSubscriptions() error {
+	_, err := s.db.Exec(`
+	CREATE TABLE IF NOT EXISTS subscriptions (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id TEXT NOT NULL,
+		channel_id TEXT NOT NULL DEFAULT '',
+		guild_id TEXT NOT NULL DEFAULT '',
+		filter TEXT NOT NULL,
+		created TIMESTAMP NOT NULL
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create subscriptions table: %v", err)
+	}
+
+	_, err = s.db.Exec(`
+	CREATE TABLE IF NOT EXISTS subscription_queue (
+		subscription_id INTEGER NOT NULL,
+		intel_id TEXT NOT NULL,
+		queued TIMESTAMP NOT NULL,
+		attempts INTEGER NOT NULL DEFAULT 0,
+		delivered TIMESTAMP,
+		PRIMARY KEY (subscription_id, intel_id)
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create subscription queue table: %v", err)
+	}
+
+	return nil
+}
+
+// subscriptionColumns are the columns read by scanSubscriptions
+const subscriptionColumns = `id, user_id, channel_id, guild_id, filter, created`
+
+// scanSubscriptions reads subscriptions from query rows
+func scanSubscriptions(rows *sql.Rows) ([]*models.Subscription, error) {
+	defer rows.Close()
+
+	var subscriptions []*models.Subscription
+	for rows.Next() {
+		var sub models.Subscription
+		var filter string
+		if err := rows.Scan(&sub.ID, &sub.UserID, &sub.ChannelID, &sub.GuildID, &filter, &sub.Created); err != nil {
+			return nil, fmt.Errorf("failed to scan subscription: %v", err)
+		}
+		if err := json.Unmarshal([]byte(filter), &sub.Filter); err != nil {
+			return nil, fmt.Errorf("failed to decode filter of subscription %d: %v", sub.ID, err)
+		}
+		subscriptions = append(subscriptions, &sub)
+	}
+	return subscriptions, nil
+}
+
+// querySubscriptions reads all subscriptions
+func querySubscriptions(q queryer) ([]*models.Subscription, error) {
+	rows, err := q.Query(`SELECT ` + subscriptionColumns + ` FROM subscriptions ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query subscriptions: %v", err)
+	}
+	return scanSubscriptions(rows)
+}
+
+// AddSubscription stores a subscription and sets its ID
+func (s *Store) AddSubscription(sub *models.Subscription) error {
+	filter, err := json.Marshal(sub.Filter)
+	if err != nil {
+		return fmt.Errorf("failed to encode filter: %v", err)
+	}
+
+	sub.Created = time.Now().UTC()
+	result, err := s.db.Exec(`
+	INSERT INTO subscriptions (user_id, channel_id, guild_id, filter, created)
+	VALUES (?, ?, ?, ?, ?)`, sub.UserID, sub.ChannelID, sub.GuildID, string(filter), sub.Created)
+	if err != nil {
+		return fmt.Errorf("failed to add subscription: %v", err)
+	}
+
+	sub.ID, err = result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to add subscription: %v", err)
+	}
+	return nil
+}
+
+// GetSubscription retrieves a subscription by ID, or nil if it doesn't exist
+func (s *Store) GetSubscription(id int64) (*models.Subscription, error) {
+	rows, err := s.db.Query(`SELECT `+subscriptionColumns+` FROM subscriptions WHERE id = ?`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query subscription: %v", err)
+	}
+
+	subscriptions, err := scanSubscriptions(rows)
+	if err != nil || len(subscriptions) == 0 {
+		return nil, err
+	}
+	return subscriptions[0], nil
+}
+
+// GetSubscriptions retrieves a user's DM subscriptions and the channel
+// subscriptions of a guild
+func (s *Store) GetSubscriptions(userID, guildID string) ([]*models.Subscription, error) {
+	rows, err := s.db.Query(`
+	SELECT `+subscriptionColumns+`
+	FROM subscriptions
+	WHERE (channel_id = '' AND user_id = ?) OR (channel_id != '' AND guild_id = ? AND guild_id != '')
+	ORDER BY id`, userID, guildID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query subscriptions: %v", err)
+	}
+	return scanSubscriptions(rows)
+}
+
+// CountSubscriptions counts the subscriptions delivered to a channel, or
+// by DM to a user if channelID is empty
+func (s *Store) CountSubscriptions(userID, channelID string) (int, error) {
+	var count int
+	var err error
+	if channelID != "" {
+		err = s.db.QueryRow(`SELECT COUNT(*) FROM subscriptions WHERE channel_id = ?`, channelID).Scan(&count)
+	} else {
+		err = s.db.QueryRow(`SELECT COUNT(*) FROM subscriptions WHERE channel_id = '' AND user_id = ?`, userID).Scan(&count)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to count subscriptions: %v", err)
+	}
+	return count, nil
+}
+
+// RemoveSubscription deletes a subscription and its queued deliveries
+func (s *Store) RemoveSubscription(id int64) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM subscription_queue WHERE subscription_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to remove subscription deliveries: %v", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM subscriptions WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to remove subscription: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return nil
+}
+
+// GetPendingDeliveries retrieves queued subscription deliveries, oldest first
+func (s *Store) GetPendingDeliveries(limit int) ([]*models.SubscriptionDelivery, error) {
+	rows, err := s.db.Query(`
+	SELECT subscription_id, intel_id
+	FROM subscription_queue
+	WHERE delivered IS NULL AND attempts < ?
+	ORDER BY queued ASC
+	LIMIT ?`, maxDeliveryAttempts, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query subscription queue: %v", err)
+	}
+
+	type pending struct {
+		subscriptionID int64
+		intelID        string
+	}
+	var queued []pending
+	for rows.Next() {
+		var p pending
+		if err := rows.Scan(&p.subscriptionID, &p.intelID); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan subscription delivery: %v", err)
+		}
+		queued = append(queued, p)
+	}
+	rows.Close()
+
+	subscriptions := make(map[int64]*models.Subscription)
+	var deliveries []*models.SubscriptionDelivery
+	for _, p := range queued {
+		sub, ok := subscriptions[p.subscriptionID]
+		if !ok {
+			if sub, err = s.GetSubscription(p.subscriptionID); err != nil {
+				return nil, err
+			}
+			subscriptions[p.subscriptionID] = sub
+		}
+
+		item, err := s.GetIntelligenceByID(p.intelID)
+		if err != nil {
+			return nil, err
+		}
+		if sub == nil || item == nil {
+			continue
+		}
+
+		deliveries = append(deliveries, &models.SubscriptionDelivery{
+			Subscription: sub,
+			Item:         item,
+		})
+	}
+
+	return deliveries, nil
+}
+
+// MarkDelivered records that an item has been delivered to a subscription
+func (s *Store) MarkDelivered(subscriptionID int64, intelID string) error {
+	_, err := s.db.Exec(`
+	UPDATE subscription_queue
+	SET delivered = ?, attempts = attempts + 1
+	WHERE subscription_id = ? AND intel_id = ?`, time.Now().UTC(), subscriptionID, intelID)
+	if err != nil {
+		return fmt.Errorf("failed to mark delivery as done: %v", err)
+	}
+	return nil
+}
+
+// MarkDeliveryFailed records a failed delivery attempt. It returns true if
+// the delivery has now been given up.
+func (s *Store) MarkDeliveryFailed(subscriptionID int64, intelID string) (bool, error) {
+	_, err := s.db.Exec(`
+	UPDATE subscription_queue
+	SET attempts = attempts + 1
+	WHERE subscription_id = ? AND intel_id = ?`, subscriptionID, intelID)
+	if err != nil {
+		return false, fmt.Errorf("failed to record delivery failure: %v", err)
+	}
+
+	var attempts int
+	err = s.db.QueryRow(`
+	SELECT attempts FROM subscription_queue
+	WHERE subscription_id = ? AND intel_id = ?`, subscriptionID, intelID).Scan(&attempts)
+	if err != nil {
+		return false, fmt.Errorf("failed to record delivery failure: %v", err)
+	}
+	return attempts >= maxDeliveryAttempts, nil
+}
@@ -1 +1,219 @@
+// internal/intel/categorizer.go
+package intel
 
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+)
+
+const (
+	// sourcePriorWeight is added to each category a source is configured with
+	sourcePriorWeight = 1.0
+	// confidenceSaturation is the score at which confidence reaches 1
+	confidenceSaturation = 4.0
+)
+
+// DefaultCategoryRules are used when no rules are configured
+var DefaultCategoryRules = []models.CategoryRule{
+	{
+		Category: models.CategoryCybersec,
+		Keywords: []string{"vulnerability", "vulnerabilities", "exploit", "exploited", "zero-day", "0-day",
+			"patch", "patches", "malware", "ransomware", "RCE", "remote code execution", "privilege escalation",
+			"XSS", "SQL injection", "backdoor", "botnet", "phishing", "advisory", "CVE", "CVSS"},
+		Patterns: []string{`(?i)\bCVE-\d{4}-\d{4,}\b`, `(?i)\bGHSA(-[a-z0-9]{4}){3}\b`},
+	},
+	{
+		Category: models.CategoryInfosecNews,
+		Keywords: []string{"breach", "data breach", "hacked", "hackers", "leak", "leaked", "arrested",
+			"indicted", "sentenced", "extortion", "threat actor", "APT", "espionage", "cybercrime",
+			"fraud", "scam", "law enforcement", "sanctions", "takedown"},
+	},
+	{
+		Category: models.CategoryAITools,
+		Keywords: []string{"AI", "LLM", "LLMs", "GPT", "ChatGPT", "OpenAI", "Anthropic", "Gemini",
+			"machine learning", "deep learning", "neural network", "language model", "generative AI",
+			"diffusion", "transformer", "copilot", "agent", "agents", "inference", "fine-tuning"},
+	},
+	{
+		Category: models.CategoryOpenSource,
+		Keywords: []string{"open source", "open-source", "GitHub", "GitLab", "repository", "maintainer",
+			"maintainers", "release", "released", "license", "Linux", "Apache", "pull request",
+			"fork", "foundation", "OSS", "package", "packages", "npm", "PyPI", "crates.io"},
+	},
+}
+
+// compiledRule is a category rule with its matchers compiled
+type compiledRule struct {
+	category models.Category
+	keywords []*regexp.Regexp
+	patterns []*regexp.Regexp
+	sources  map[string]bool
+	weight   float64
+}
+
+// Categorizer assigns a primary category and secondary categories to items
+// from keyword, pattern and source rules
+type Categorizer struct {
+	sources       map[string]models.FeedSource
+	rules         []compiledRule
+	minConfidence float64
+}
+
+// NewCategorizer creates a categorizer for the configured sources. The
+// default rules are used if rules is empty. Secondary categories are kept
+// when their confidence is at least minConfidence.
+func NewCategorizer(sources []models.FeedSource, rules []models.CategoryRule, minConfidence float64) (*Categorizer, error) {
+	if len(rules) == 0 {
+		rules = DefaultCategoryRules
+	}
+
+	c := &Categorizer{
+		sources:       make(map[string]models.FeedSource),
+		minConfidence: minConfidence,
+	}
+	for _, source := range sources {
+		c.sources[source.ID] = source
+	}
+
+	for i, rule := range rules {
+		compiled := compiledRule{
+			category: rule.Category,
+			weight:   rule.Weight,
+		}
+		if compiled.weight <= 0 {
+			compiled.weight = 1
+		}
+
+		for _, keyword := range rule.Keywords {
+			re, err := regexp.Compile(`(?i)\b` + regexp.QuoteMeta(keyword) + `\b`)
+			if err != nil {
+				return nil, fmt.Errorf("category rule %d: invalid keyword %q: %v", i, keyword, err)
+			}
+			compiled.keywords = append(compiled.keywords, re)
+		}
+
+		for _, pattern := range rule.Patterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("category rule %d: invalid pattern %q: %v", i, pattern, err)
+			}
+			compiled.patterns = append(compiled.patterns, re)
+		}
+
+		if len(rule.Sources) > 0 {
+			compiled.sources = make(map[string]bool)
+			for _, id := range rule.Sources {
+				compiled.sources[id] = true
+			}
+		}
+
+		c.rules = append(c.rules, compiled)
+	}
+
+	return c, nil
+}
+
+// Name returns the stage name
+func (c *Categorizer) Name() string {
+	return "categorize"
+}
+
+// Process categorizes each item
+func (c *Categorizer) Process(items []*models.Intelligence) ([]*models.Intelligence, error) {
+	for _, item := range items {
+		scores := c.Classify(item)
+		if len(scores) == 0 {
+			// Nothing matched, so keep the parser's category, which was set
+			// by the source and is recorded with full confidence
+			if item.Category == "" {
+				item.Category = models.CategoryInfosecNews
+			}
+			item.Categories = []models.CategoryScore{{Category: item.Category, Confidence: 1}}
+			continue
+		}
+
+		item.Category = scores[0].Category
+		item.Categories = scores[:1]
+		for _, score := range scores[1:] {
+			if score.Confidence >= c.minConfidence {
+				item.Categories = append(item.Categories, score)
+			}
+		}
+	}
+	return items, nil
+}
+
+// Classify scores every category that an item matches, highest first
+func (c *Categorizer) Classify(item *models.Intelligence) []models.CategoryScore {
+	scores := make(map[models.Category]float64)
+	order := make(map[models.Category]int)
+
+	// Source categories are a prior, in configured order
+	if source, ok := c.sources[item.SourceID]; ok {
+		for i, category := range source.Categories {
+			scores[category] += sourcePriorWeight
+			if _, seen := order[category]; !seen {
+				order[category] = i
+			}
+		}
+	}
+
+	for _, rule := range c.rules {
+		if rule.sources != nil && !rule.sources[item.SourceID] {
+			continue
+		}
+
+		score := 0.0
+		for _, re := range rule.keywords {
+			if re.MatchString(item.Title) {
+				score += 2 * rule.weight
+			} else if re.MatchString(item.Summary) {
+				score += rule.weight
+			}
+		}
+		for _, re := range rule.patterns {
+			if re.MatchString(item.Title) || re.MatchString(item.Summary) {
+				score += rule.weight
+			}
+		}
+
+		// A source-only rule is a fixed weight for that source
+		if rule.sources != nil && len(rule.keywords) == 0 && len(rule.patterns) == 0 {
+			score += rule.weight
+		}
+
+		if score > 0 {
+			scores[rule.category] += score
+			if _, seen := order[rule.category]; !seen {
+				order[rule.category] = len(order) + 100
+			}
+		}
+	}
+
+	result := make([]models.CategoryScore, 0, len(scores))
+	for category, score := range scores {
+		confidence := score / confidenceSaturation
+		if confidence > 1 {
+			confidence = 1
+		}
+		result = append(result, models.CategoryScore{Category: category, Confidence: confidence})
+	}
+
+	// Highest score first, ties broken by source order then name
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if scores[a.Category] != scores[b.Category] {
+			return scores[a.Category] > scores[b.Category]
+		}
+		if order[a.Category] != order[b.Category] {
+			return order[a.Category] < order[b.Category]
+		}
+		return strings.Compare(string(a.Category), string(b.Category)) < 0
+	})
+
+	return result
+}
@@ -0,0 +1,71 @@
+// internal/intel/categorizer_test.go
+package intel
+
+import (
+	"testing"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+)
+
+func TestCategorizer(t *testing.T) {
+	sources := []models.FeedSource{
+		{ID: "vendor-blog", Categories: []models.Category{models.CategoryOpenSource}},
+	}
+	categorizer, err := NewCategorizer(sources, nil, 0.25)
+	if err != nil {
+		t.Fatalf("failed to create categorizer: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		item models.Intelligence
+		want []models.CategoryScore
+	}{
+		{
+			// Two title keywords and a CVE pattern saturate the confidence
+			name: "keywords and patterns",
+			item: models.Intelligence{SourceID: "unknown", Title: "Exploited vulnerability CVE-2024-3094 in xz"},
+			want: []models.CategoryScore{{Category: models.CategoryCybersec, Confidence: 1}},
+		},
+		{
+			// The source category is a prior, outscored by a title keyword
+			name: "source prior",
+			item: models.Intelligence{SourceID: "vendor-blog", Title: "New ransomware strain spreads"},
+			want: []models.CategoryScore{
+				{Category: models.CategoryCybersec, Confidence: 0.5},
+				{Category: models.CategoryOpenSource, Confidence: 0.25},
+			},
+		},
+		{
+			// Unscored items keep the parser's category with full confidence
+			name: "nothing matched",
+			item: models.Intelligence{SourceID: "unknown", Category: models.CategoryAITools, Title: "Weekly notes"},
+			want: []models.CategoryScore{{Category: models.CategoryAITools, Confidence: 1}},
+		},
+		{
+			name: "nothing matched without a category",
+			item: models.Intelligence{SourceID: "unknown", Title: "Weekly notes"},
+			want: []models.CategoryScore{{Category: models.CategoryInfosecNews, Confidence: 1}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			item := test.item
+			if _, err := categorizer.Process([]*models.Intelligence{&item}); err != nil {
+				t.Fatalf("failed to categorize: %v", err)
+			}
+			if item.Category != test.want[0].Category {
+				t.Errorf("category = %s, want %s", item.Category, test.want[0].Category)
+			}
+			if len(item.Categories) != len(test.want) {
+				t.Fatalf("categories = %v, want %v", item.Categories, test.want)
+			}
+			for i, score := range item.Categories {
+				if score != test.want[i] {
+					t.Errorf("categories[%d] = %v, want %v", i, score, test.want[i])
+				}
+			}
+		})
+	}
+}
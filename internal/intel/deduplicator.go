@@ -1 +1,387 @@
+// internal/intel/deduplicator.go
+package intel
 
+import (
+	"hash/fnv"
+	"math/bits"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+	"github.com/NullMeDev/Infopulse-Node/pkg/utils"
+)
+
+const (
+	// ClusterWindow is how far back items are compared for duplicates
+	ClusterWindow = 72 * time.Hour
+	// minTitleTokens is the number of significant words a title needs
+	// before it is compared with other titles
+	minTitleTokens = 4
+	// maxSimHashDistance is the number of differing SimHash bits at which
+	// two titles are treated as the same
+	maxSimHashDistance = 3
+	// minTitleSimilarity is the word overlap (Jaccard index) at which two
+	// titles are treated as the same story
+	minTitleSimilarity = 0.6
+)
+
+// trackingParams are query parameters that do not change the page
+var trackingParams = map[string]bool{
+	"fbclid": true, "gclid": true, "mc_cid": true, "mc_eid": true,
+	"ref": true, "ref_src": true, "outputtype": true,
+}
+
+// recordMethods are the fetch methods of sources that publish records of
+// their own, such as vulnerability database entries. Their items are only
+// clustered with items of the same method, never with reports.
+var recordMethods = map[string]bool{
+	"nvd": true, "cisa-kev": true, "osv": true, "releases": true,
+}
+
+// stopWords are ignored when comparing titles
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true,
+	"by": true, "for": true, "from": true, "has": true, "have": true, "in": true, "into": true,
+	"is": true, "it": true, "its": true, "new": true, "of": true, "on": true, "or": true,
+	"over": true, "the": true, "to": true, "via": true, "was": true, "with": true,
+}
+
+// story is the fingerprint of an item that has been seen
+type story struct {
+	id        string
+	clusterID string
+	sourceID  string
+	kind      string
+	url       string
+	cves      []string
+	tokens    map[string]bool
+	simhash   uint64
+	seen      time.Time
+}
+
+// Deduplicator drops exact duplicates and groups near-duplicates reported
+// by different sources of the same kind into story clusters. The first
+// item of a story is its canonical item; later reports get its ID as their
+// ClusterID.
+type Deduplicator struct {
+	mu      sync.Mutex
+	kinds   map[string]string
+	stories []*story
+	byID    map[string]*story
+	byURL   map[string]*story // by kind and URL
+	byCVE   map[string]*story // by kind and CVE
+}
+
+// NewDeduplicator creates a deduplicator for the configured sources
+func NewDeduplicator(sources []models.FeedSource) *Deduplicator {
+	d := &Deduplicator{
+		kinds: make(map[string]string),
+		byID:  make(map[string]*story),
+		byURL: make(map[string]*story),
+		byCVE: make(map[string]*story),
+	}
+	for _, source := range sources {
+		if recordMethods[source.FetchMethod] {
+			d.kinds[source.ID] = source.FetchMethod
+		}
+	}
+	return d
+}
+
+// Name returns the stage name
+func (d *Deduplicator) Name() string {
+	return "dedupe"
+}
+
+// Seed adds stored items so new items can be clustered with them. Call it
+// with recently stored items at startup and with each batch once it is saved.
+func (d *Deduplicator) Seed(items []*models.Intelligence) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// Oldest first, so canonical items are indexed before their alternates
+	sorted := append([]*models.Intelligence(nil), items...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Retrieved.Before(sorted[j].Retrieved)
+	})
+
+	for _, item := range sorted {
+		if _, exists := d.byID[item.ID]; exists {
+			continue
+		}
+		clusterID := item.ClusterID
+		if clusterID == "" {
+			clusterID = item.ID
+		}
+		d.add(d.fingerprint(item, clusterID), item.Retrieved)
+	}
+}
+
+// Process drops items whose ID or hash repeats an earlier item in the batch
+// and links near-duplicates to the story they belong to. Items are only
+// compared with stories of other sources, and are not remembered until
+// they are passed to Seed after being saved.
+func (d *Deduplicator) Process(items []*models.Intelligence) ([]*models.Intelligence, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now().UTC()
+	d.prune(now.Add(-ClusterWindow))
+
+	seen := make(map[string]bool)
+	kept := items[:0:0]
+
+	for _, item := range items {
+		if seen[item.ID] || seen[item.Hash] {
+			continue
+		}
+		seen[item.ID] = true
+		seen[item.Hash] = true
+
+		// An item that was seen before keeps its cluster
+		if previous, ok := d.byID[item.ID]; ok {
+			if previous.clusterID != item.ID {
+				item.ClusterID = previous.clusterID
+			}
+			kept = append(kept, item)
+			continue
+		}
+
+		if match := d.match(d.fingerprint(item, item.ID)); match != nil {
+			item.ClusterID = match.clusterID
+		}
+		kept = append(kept, item)
+	}
+
+	return kept, nil
+}
+
+// match finds the story an item belongs to
+func (d *Deduplicator) match(current *story) *story {
+	if s, ok := d.byURL[indexKey(current.kind, current.url)]; ok && current.url != "" && related(s, current) {
+		return s
+	}
+
+	for _, cve := range current.cves {
+		if s, ok := d.byCVE[indexKey(current.kind, cve)]; ok && related(s, current) {
+			return s
+		}
+	}
+
+	if len(current.tokens) < minTitleTokens {
+		return nil
+	}
+
+	var best *story
+	bestScore := 0.0
+	for _, s := range d.stories {
+		if !related(s, current) || len(s.tokens) < minTitleTokens {
+			continue
+		}
+
+		// Items about different vulnerabilities are different stories,
+		// however similar their titles
+		if len(s.cves) > 0 && len(current.cves) > 0 {
+			continue
+		}
+
+		if bits.OnesCount64(s.simhash^current.simhash) <= maxSimHashDistance {
+			return s
+		}
+		if score := jaccard(s.tokens, current.tokens); score >= minTitleSimilarity && score > bestScore {
+			best, bestScore = s, score
+		}
+	}
+
+	return best
+}
+
+// related reports whether two stories may be reports of the same story:
+// they come from different sources of the same kind
+func related(a, b *story) bool {
+	return a.sourceID != b.sourceID && a.kind == b.kind
+}
+
+// indexKey keys the URL and CVE indexes, which are kept per kind of source
+func indexKey(kind, value string) string {
+	return kind + " " + value
+}
+
+// add indexes a story
+func (d *Deduplicator) add(s *story, seen time.Time) {
+	s.seen = seen
+	d.stories = append(d.stories, s)
+	d.byID[s.id] = s
+
+	if s.url != "" {
+		if _, exists := d.byURL[indexKey(s.kind, s.url)]; !exists {
+			d.byURL[indexKey(s.kind, s.url)] = s
+		}
+	}
+	for _, cve := range s.cves {
+		if _, exists := d.byCVE[indexKey(s.kind, cve)]; !exists {
+			d.byCVE[indexKey(s.kind, cve)] = s
+		}
+	}
+}
+
+// prune forgets stories seen before the cutoff
+func (d *Deduplicator) prune(cutoff time.Time) {
+	kept := d.stories[:0]
+	for _, s := range d.stories {
+		if s.seen.Before(cutoff) {
+			delete(d.byID, s.id)
+			if d.byURL[indexKey(s.kind, s.url)] == s {
+				delete(d.byURL, indexKey(s.kind, s.url))
+			}
+			for _, cve := range s.cves {
+				if d.byCVE[indexKey(s.kind, cve)] == s {
+					delete(d.byCVE, indexKey(s.kind, cve))
+				}
+			}
+			continue
+		}
+		kept = append(kept, s)
+	}
+	d.stories = kept
+}
+
+// fingerprint computes the values an item is compared by
+func (d *Deduplicator) fingerprint(item *models.Intelligence, clusterID string) *story {
+	tokens := titleTokens(item.Title)
+
+	// Only the CVE an item is about counts, not every CVE it mentions
+	var cves []string
+	if item.CVEID != "" {
+		cves = append(cves, strings.ToUpper(item.CVEID))
+	}
+	for _, cve := range utils.ExtractCVEs(strings.Join(item.Aliases, " ")) {
+		if len(cves) == 0 || cves[0] != cve {
+			cves = append(cves, cve)
+		}
+	}
+
+	return &story{
+		id:        item.ID,
+		clusterID: clusterID,
+		sourceID:  item.SourceID,
+		kind:      d.kinds[item.SourceID],
+		url:       NormalizeURL(item.URL),
+		cves:      cves,
+		tokens:    tokens,
+		simhash:   simHash(tokens),
+	}
+}
+
+// NormalizeURL reduces a URL to a canonical form for comparison: lower-case
+// host without "www." or "amp.", no AMP suffix, fragment or tracking
+// parameters, and remaining parameters sorted
+func NormalizeURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return strings.TrimSpace(raw)
+	}
+
+	host := strings.ToLower(u.Hostname())
+	path := u.Path
+
+	// Google AMP cache: <host>.cdn.ampproject.org/c/s/<host>/<path>
+	if strings.HasSuffix(host, ".cdn.ampproject.org") {
+		segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+		if len(segments) > 0 && segments[0] == "c" {
+			segments = segments[1:]
+		}
+		if len(segments) > 0 && segments[0] == "s" {
+			segments = segments[1:]
+		}
+		if len(segments) > 0 && segments[0] != "" {
+			host = strings.ToLower(segments[0])
+			path = "/" + strings.Join(segments[1:], "/")
+		}
+	}
+
+	host = strings.TrimPrefix(host, "www.")
+	host = strings.TrimPrefix(host, "amp.")
+
+	// AMP pages: /amp, /amp/ and /article.amp.html
+	path = strings.TrimSuffix(path, "/")
+	path = strings.TrimSuffix(path, "/amp")
+	path = strings.TrimSuffix(path, ".amp.html")
+	path = strings.TrimSuffix(path, ".amp")
+	path = strings.TrimSuffix(path, "/")
+
+	query := u.Query()
+	for key := range query {
+		lower := strings.ToLower(key)
+		if strings.HasPrefix(lower, "utm_") || trackingParams[lower] {
+			query.Del(key)
+		}
+	}
+
+	normalized := host + path
+	if encoded := query.Encode(); encoded != "" {
+		normalized += "?" + encoded
+	}
+	return normalized
+}
+
+// titleTokens splits a title into its significant lower-case words
+func titleTokens(title string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-'
+	})
+
+	tokens := make(map[string]bool)
+	for _, word := range words {
+		word = strings.Trim(word, "-")
+		if len(word) < 2 || stopWords[word] {
+			continue
+		}
+		tokens[word] = true
+	}
+	return tokens
+}
+
+// simHash computes a 64-bit SimHash of a set of words
+func simHash(tokens map[string]bool) uint64 {
+	var weights [64]int
+	for token := range tokens {
+		h := fnv.New64a()
+		h.Write([]byte(token))
+		sum := h.Sum64()
+		for i := 0; i < 64; i++ {
+			if sum&(1<<uint(i)) != 0 {
+				weights[i]++
+			} else {
+				weights[i]--
+			}
+		}
+	}
+
+	var hash uint64
+	for i, weight := range weights {
+		if weight > 0 {
+			hash |= 1 << uint(i)
+		}
+	}
+	return hash
+}
+
+// jaccard returns the overlap of two word sets
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for token := range a {
+		if b[token] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
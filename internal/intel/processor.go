@@ -1 +1,204 @@
+// internal/intel/processor.go
+package intel
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/logger"
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+)
+
+// Stage is a step of the processing pipeline. A stage returns the items to
+// pass on, which may be fewer than it received. If it returns an
+// *ItemErrors the returned items are used and each error is counted;
+// any other error skips the stage and its input is passed on unchanged.
+type Stage interface {
+	Name() string
+	Process(items []*models.Intelligence) ([]*models.Intelligence, error)
+}
+
+// ItemErrors collects per-item failures of a stage
+type ItemErrors []error
+
+// Error implements the error interface
+func (e *ItemErrors) Error() string {
+	messages := make([]string, 0, len(*e))
+	for _, err := range *e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// itemStage adapts a per-item function to a Stage. Items whose function
+// returns an error are dropped and the error is recorded.
+type itemStage struct {
+	name string
+	fn   func(item *models.Intelligence) error
+}
+
+// ItemStage creates a stage that applies fn to each item
+func ItemStage(name string, fn func(item *models.Intelligence) error) Stage {
+	return &itemStage{name: name, fn: fn}
+}
+
+// Name returns the stage name
+func (s *itemStage) Name() string {
+	return s.name
+}
+
+// Process applies the stage function to each item
+func (s *itemStage) Process(items []*models.Intelligence) ([]*models.Intelligence, error) {
+	var errs ItemErrors
+	kept := items[:0:0]
+
+	for _, item := range items {
+		if err := s.fn(item); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", item.ID, err))
+			continue
+		}
+		kept = append(kept, item)
+	}
+
+	if len(errs) > 0 {
+		return kept, &errs
+	}
+	return kept, nil
+}
+
+// StageMetrics records the activity of a pipeline stage
+type StageMetrics struct {
+	Name        string        `json:"name"`        // Stage name
+	Runs        int64         `json:"runs"`        // Number of batches processed
+	ItemsIn     int64         `json:"itemsIn"`     // Items received
+	ItemsOut    int64         `json:"itemsOut"`    // Items passed on
+	Errors      int64         `json:"errors"`      // Item and stage errors
+	Failures    int64         `json:"failures"`    // Runs where the whole stage was skipped
+	Duration    time.Duration `json:"duration"`    // Total processing time
+	LastError   string        `json:"lastError"`   // Most recent error
+	LastErrorAt time.Time     `json:"lastErrorAt"` // When the most recent error occurred
+}
+
+// Processor runs intelligence items through a pipeline of stages
+type Processor struct {
+	mu      sync.Mutex
+	stages  []Stage
+	metrics map[string]*StageMetrics
+	logger  *logger.Logger
+}
+
+// NewProcessor creates a processor with the given stages
+func NewProcessor(logger *logger.Logger, stages ...Stage) *Processor {
+	p := &Processor{
+		metrics: make(map[string]*StageMetrics),
+		logger:  logger,
+	}
+
+	for _, stage := range stages {
+		p.AddStage(stage)
+	}
+
+	return p
+}
+
+// AddStage appends a stage to the pipeline
+func (p *Processor) AddStage(stage Stage) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.stages = append(p.stages, stage)
+	if _, exists := p.metrics[stage.Name()]; !exists {
+		p.metrics[stage.Name()] = &StageMetrics{Name: stage.Name()}
+	}
+}
+
+// Process runs items through every stage in order
+func (p *Processor) Process(items []*models.Intelligence) []*models.Intelligence {
+	p.mu.Lock()
+	stages := append([]Stage(nil), p.stages...)
+	p.mu.Unlock()
+
+	for _, stage := range stages {
+		if len(items) == 0 {
+			break
+		}
+
+		start := time.Now()
+		out, err := stage.Process(items)
+		elapsed := time.Since(start)
+
+		var itemErrs *ItemErrors
+		failed := err != nil && !errors.As(err, &itemErrs)
+		if failed {
+			// Skip the stage and pass its input on
+			p.logger.Error("Processor", fmt.Sprintf("Stage %s failed: %v", stage.Name(), err))
+			out = items
+		} else if itemErrs != nil {
+			p.logger.Warning("Processor", fmt.Sprintf("Stage %s dropped %d items: %v", stage.Name(), len(*itemErrs), err))
+		}
+
+		p.record(stage.Name(), len(items), len(out), elapsed, err, itemErrs, failed)
+		items = out
+	}
+
+	return items
+}
+
+// record updates the metrics of a stage
+func (p *Processor) record(name string, in, out int, elapsed time.Duration, err error, itemErrs *ItemErrors, failed bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	m := p.metrics[name]
+	m.Runs++
+	m.ItemsIn += int64(in)
+	m.ItemsOut += int64(out)
+	m.Duration += elapsed
+
+	if err == nil {
+		return
+	}
+
+	if itemErrs != nil {
+		m.Errors += int64(len(*itemErrs))
+	} else {
+		m.Errors++
+	}
+	if failed {
+		m.Failures++
+	}
+	m.LastError = err.Error()
+	m.LastErrorAt = time.Now().UTC()
+}
+
+// Metrics returns a snapshot of the metrics of each stage in pipeline order
+func (p *Processor) Metrics() []StageMetrics {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	metrics := make([]StageMetrics, 0, len(p.stages))
+	for _, stage := range p.stages {
+		metrics = append(metrics, *p.metrics[stage.Name()])
+	}
+	return metrics
+}
+
+// DefaultStages returns the standard pipeline:
+// normalize → enrich → categorize → dedupe → score
+func DefaultStages(sources []models.FeedSource, rules []models.CategoryRule, minConfidence float64) ([]Stage, error) {
+	categorizer, err := NewCategorizer(sources, rules, minConfidence)
+	if err != nil {
+		return nil, err
+	}
+
+	return []Stage{
+		NewNormalizer(),
+		NewEnricher(),
+		categorizer,
+		NewDeduplicator(sources),
+		NewScorer(),
+	}, nil
+}
@@ -0,0 +1,180 @@
+// internal/intel/stages.go
+package intel
+
+import (
+	"errors"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+	"github.com/NullMeDev/Infopulse-Node/pkg/utils"
+)
+
+var (
+	// tagPattern matches HTML tags, including one cut off by truncation
+	tagPattern = regexp.MustCompile(`<[^>]*(>|$)`)
+	// spacePattern matches runs of horizontal whitespace
+	spacePattern = regexp.MustCompile(`[ \t\r\f\v]+`)
+	// blankLinePattern matches runs of blank lines
+	blankLinePattern = regexp.MustCompile(`\n\s*\n(\s*\n)+`)
+)
+
+// NewNormalizer creates the stage that cleans up item text and dates
+func NewNormalizer() Stage {
+	return ItemStage("normalize", normalize)
+}
+
+// normalize cleans up an item in place. The title and URL are cleaned the
+// same way parsers clean them before deriving IDs and hashes, so the ID and
+// hash still match the item.
+func normalize(item *models.Intelligence) error {
+	item.Title, item.URL = NormalizeTitleURL(item.Title, item.URL)
+	item.Summary = StripHTML(item.Summary)
+	item.Severity = strings.ToUpper(strings.TrimSpace(item.Severity))
+
+	if item.Title == "" {
+		return errors.New("item has no title or URL")
+	}
+
+	// Fix missing and future publication dates
+	if item.Retrieved.IsZero() {
+		item.Retrieved = time.Now().UTC()
+	}
+	if item.Published.IsZero() || item.Published.After(item.Retrieved.Add(time.Hour)) {
+		item.Published = item.Retrieved
+	}
+
+	return nil
+}
+
+// NormalizeTitleURL cleans up the title and URL of an item, using the URL
+// as the title of untitled items
+func NormalizeTitleURL(title, url string) (string, string) {
+	title = strings.Join(strings.Fields(html.UnescapeString(title)), " ")
+	url = strings.TrimSpace(url)
+	if title == "" {
+		title = url
+	}
+	return title, url
+}
+
+// StripHTML removes tags and entities from text and tidies whitespace,
+// keeping paragraph breaks
+func StripHTML(text string) string {
+	text = strings.NewReplacer("<br>", "\n", "<br/>", "\n", "<br />", "\n", "</p>", "\n\n").Replace(text)
+	text = tagPattern.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+	text = spacePattern.ReplaceAllString(text, " ")
+	text = blankLinePattern.ReplaceAllString(text, "\n\n")
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// NewEnricher creates the stage that derives CVE IDs and severities
+func NewEnricher() Stage {
+	return ItemStage("enrich", enrich)
+}
+
+// enrich fills in fields that can be derived from other fields
+func enrich(item *models.Intelligence) error {
+	// Take the CVE from the title, or from the summary if it names only one
+	if item.CVEID == "" {
+		if cves := utils.ExtractCVEs(item.Title); len(cves) > 0 {
+			item.CVEID = cves[0]
+		} else if cves := utils.ExtractCVEs(item.Summary); len(cves) == 1 {
+			item.CVEID = cves[0]
+		}
+	}
+
+	// Derive severity from the CVSS score
+	if item.Severity == "" && item.CVSSScore > 0 {
+		item.Severity = SeverityFromScore(item.CVSSScore)
+	}
+
+	return nil
+}
+
+// SeverityFromScore maps a CVSS base score to its qualitative rating
+func SeverityFromScore(score float64) string {
+	switch {
+	case score >= 9.0:
+		return "CRITICAL"
+	case score >= 7.0:
+		return "HIGH"
+	case score >= 4.0:
+		return "MEDIUM"
+	case score > 0:
+		return "LOW"
+	default:
+		return ""
+	}
+}
+
+// SeverityRank orders severity levels from 1 for LOW to 4 for CRITICAL.
+// Unknown or empty severities rank 0.
+func SeverityRank(severity string) int {
+	switch strings.ToUpper(severity) {
+	case "CRITICAL":
+		return 4
+	case "HIGH":
+		return 3
+	case "MEDIUM":
+		return 2
+	case "LOW":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// NewScorer creates the stage that assigns each item a priority score
+func NewScorer() Stage {
+	return ItemStage("score", func(item *models.Intelligence) error {
+		item.Score = Score(item, time.Now().UTC())
+		return nil
+	})
+}
+
+// Score rates an item from 0 to 100 by severity, CVSS score, exploitation
+// and recency
+func Score(item *models.Intelligence, now time.Time) float64 {
+	score := 0.0
+
+	switch item.Severity {
+	case "CRITICAL":
+		score += 40
+	case "HIGH":
+		score += 30
+	case "MEDIUM":
+		score += 20
+	case "LOW":
+		score += 10
+	default:
+		score += 5
+	}
+
+	score += item.CVSSScore * 2
+
+	if item.Exploited {
+		score += 30
+	}
+
+	age := now.Sub(item.Published)
+	switch {
+	case age < 24*time.Hour:
+		score += 10
+	case age < 7*24*time.Hour:
+		score += 5
+	}
+
+	if score > 100 {
+		score = 100
+	}
+	return score
+}
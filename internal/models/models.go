@@ -15,11 +15,33 @@ const (
 	CategoryInfosecNews Category = "INFOSEC_NEWS"
 )
 
+// Categories lists every category
+var Categories = []Category{CategoryCybersec, CategoryAITools, CategoryOpenSource, CategoryInfosecNews}
+
+// CategoryScore is a category assigned to an item with a confidence from 0 to 1
+type CategoryScore struct {
+	Category   Category `json:"category"`
+	Confidence float64  `json:"confidence"`
+}
+
+// CategoryRule adds weight to a category when an item matches. Keywords
+// match whole words case-insensitively and count double in titles;
+// patterns are regular expressions; sources restrict the rule to items
+// from those source IDs, or add weight on their own if nothing else is set.
+type CategoryRule struct {
+	Category Category `json:"category"`           // Category the rule votes for
+	Keywords []string `json:"keywords,omitempty"` // Keywords to look for
+	Patterns []string `json:"patterns,omitempty"` // Regular expressions to look for
+	Sources  []string `json:"sources,omitempty"`  // Source IDs the rule applies to
+	Weight   float64  `json:"weight,omitempty"`   // Weight per match (default 1)
+}
+
 // Intelligence represents an intelligence item
 type Intelligence struct {
 	ID        string    `json:"id"`        // Unique identifier
 	SourceID  string    `json:"sourceId"`  // ID of the source feed
 	Category  Category  `json:"category"`  // Primary category
+	Categories []CategoryScore `json:"categories,omitempty"` // All relevant categories, primary first
 	Title     string    `json:"title"`     // Title of the item
 	URL       string    `json:"url"`       // URL to the original content
 	Summary   string    `json:"summary"`   // Summary or excerpt
@@ -27,6 +49,93 @@ type Intelligence struct {
 	Retrieved time.Time `json:"retrieved"` // When the item was retrieved
 	Hash      string    `json:"hash"`      // Hash for deduplication
 	Severity  string    `json:"severity"`  // Severity (for CVEs and vulnerabilities)
+	Score     float64   `json:"score"`     // Priority score from 0 to 100
+
+	// Vulnerability details, set by sources that provide them
+	CVEID      string   `json:"cveId,omitempty"`      // CVE identifier
+	CVSSScore  float64  `json:"cvssScore,omitempty"`  // CVSS base score
+	CVSSVector string   `json:"cvssVector,omitempty"` // CVSS vector string
+	CPEs       []string `json:"cpes,omitempty"`       // Affected platforms as CPE 2.3 names
+
+	// Modified is when the source last revised the record. Sources that set
+	// it publish updated records under the same ID, and a stored item is
+	// replaced when a newer revision arrives.
+	Modified time.Time `json:"modified,omitempty"`
+
+	// Backfill marks items from the first fetch of a source that were
+	// published before its lookback. They are stored and searchable, but
+	// not delivered to autopost, subscriptions or sinks.
+	Backfill bool `json:"backfill,omitempty"`
+
+	// Exploited marks items about actively exploited vulnerabilities. Parsers
+	// set it only when the source itself lists CVEID as exploited (e.g. the
+	// CISA KEV catalog); the store then flags every item mentioning that CVE.
+	Exploited bool `json:"exploited,omitempty"`
+
+	// Advisory details, set by package advisory sources such as OSV
+	Aliases  []string          `json:"aliases,omitempty"`  // Other identifiers (GHSA, CVE, ...)
+	Affected []AffectedPackage `json:"affected,omitempty"` // Affected packages
+
+	// Release details, set by release tracking sources
+	Version    string `json:"version,omitempty"`    // Released version or tag
+	Prerelease bool   `json:"prerelease,omitempty"` // Whether the release is a pre-release
+
+	// Story clustering. ClusterID is the ID of the canonical item when this
+	// item reports the same story; it is empty for canonical items, whose
+	// Alternates list the other reports.
+	ClusterID  string      `json:"clusterId,omitempty"`
+	Alternates []Alternate `json:"alternates,omitempty"`
+}
+
+// Alternate is another report of the same story
+type Alternate struct {
+	ID       string `json:"id"`       // ID of the alternate item
+	SourceID string `json:"sourceId"` // Source that reported it
+	Title    string `json:"title"`    // Title of the report
+	URL      string `json:"url"`      // URL of the report
+}
+
+// AffectedPackage describes a package affected by an advisory
+type AffectedPackage struct {
+	Ecosystem string         `json:"ecosystem"`          // Package ecosystem (Go, npm, PyPI, ...)
+	Name      string         `json:"name"`               // Package name within the ecosystem
+	Ranges    []VersionRange `json:"ranges,omitempty"`   // Affected version ranges
+	Versions  []string       `json:"versions,omitempty"` // Explicitly enumerated affected versions
+}
+
+// VersionRange is a range of affected versions. Introduced is inclusive;
+// Fixed is exclusive and LastAffected inclusive, and at most one is set.
+type VersionRange struct {
+	Type         string `json:"type"`                   // Version scheme (SEMVER, ECOSYSTEM, GIT)
+	Introduced   string `json:"introduced"`             // First affected version ("0" for all)
+	Fixed        string `json:"fixed,omitempty"`        // First fixed version
+	LastAffected string `json:"lastAffected,omitempty"` // Last affected version
+}
+
+// String formats the range for display
+func (r VersionRange) String() string {
+	lower := ">= " + r.Introduced
+	if r.Introduced == "" || r.Introduced == "0" {
+		lower = ""
+	}
+
+	upper := ""
+	if r.Fixed != "" {
+		upper = "< " + r.Fixed
+	} else if r.LastAffected != "" {
+		upper = "<= " + r.LastAffected
+	}
+
+	switch {
+	case lower != "" && upper != "":
+		return lower + ", " + upper
+	case lower != "":
+		return lower
+	case upper != "":
+		return upper
+	default:
+		return "all versions"
+	}
 }
 
 // FeedSource represents a source of intelligence
@@ -38,4 +147,148 @@ type FeedSource struct {
 	FetchMethod string    `json:"fetchMethod"` // Method used to fetch (rss, api, etc.)
 	UpdateFreq int        `json:"updateFreq"`  // Update frequency in minutes
 	Enabled    bool       `json:"enabled"`     // Whether this feed is enabled
+
+	// Settings of particular fetch methods
+	HTML     *HTMLSelectors `json:"html,omitempty"`     // Where items are found on the page (html)
+	JSON     *JSONMapping   `json:"json,omitempty"`     // Where items are found in the response (json)
+	Releases *ReleaseWatch  `json:"releases,omitempty"` // Repositories to watch (releases)
+}
+
+// HTMLSelectors locate items on a web page. Each field other than Item and
+// DateLayout is a CSS selector relative to the item element, optionally
+// followed by @attribute to read an attribute instead of the text, e.g.
+// "a.title@href". A selector of just "@attribute" reads the item element.
+type HTMLSelectors struct {
+	Item       string `json:"item"`                 // Selector of each item's container
+	Title      string `json:"title"`                // Title text
+	Link       string `json:"link,omitempty"`       // Link (default: the title's link, else the first link in the item)
+	Date       string `json:"date,omitempty"`       // Publication date (default: the fetch time)
+	DateLayout string `json:"dateLayout,omitempty"` // Go time layout of the date (default: common formats)
+	Summary    string `json:"summary,omitempty"`    // Summary text
+}
+
+// JSONMapping locates items in a JSON API response. Paths are dot-separated
+// keys and array indexes such as "data.children" or "tags[0].name", with
+// "$" for the whole document. Field paths are relative to each item; a
+// field may also be a template such as "https://example.com/item/{id}",
+// and alternatives separated by "|" are tried in order until one has a
+// value.
+type JSONMapping struct {
+	Items      string `json:"items"`                // Path of the item array (default: the document)
+	Title      string `json:"title"`                // Title
+	URL        string `json:"url,omitempty"`        // Link, resolved against the source URL
+	Summary    string `json:"summary,omitempty"`    // Summary text
+	Published  string `json:"published,omitempty"`  // Publication date (default: the fetch time)
+	DateLayout string `json:"dateLayout,omitempty"` // Go time layout, "unix" or "unixms" (default: detected)
+	Severity   string `json:"severity,omitempty"`   // Severity label (default: from the title)
+
+	// Pagination, with either a cursor from each response or a page number
+	Cursor      string `json:"cursor,omitempty"`      // Path of the next-page cursor, or of the next page's URL
+	CursorParam string `json:"cursorParam,omitempty"` // Query parameter the cursor is sent in (default: the cursor is a URL)
+	PageParam   string `json:"pageParam,omitempty"`   // Query parameter of the page number
+	FirstPage   int    `json:"firstPage,omitempty"`   // Number of the first page (default: 0)
+	MaxPages    int    `json:"maxPages,omitempty"`    // Pages requested per fetch (default: 5 when paginated)
+}
+
+// ReleaseWatch lists the repositories watched by a releases source, whose
+// URL is the API base such as https://api.github.com or
+// https://gitlab.com/api/v4 (default: the provider's public API)
+type ReleaseWatch struct {
+	Provider        string   `json:"provider,omitempty"`        // github or gitlab (default: github)
+	Repositories    []string `json:"repositories"`              // owner/name, or the full project path on GitLab
+	Tags            bool     `json:"tags,omitempty"`            // Watch tags, for repositories that don't publish releases
+	SkipPrereleases bool     `json:"skipPrereleases,omitempty"` // Ignore pre-releases
+	MajorMinorOnly  bool     `json:"majorMinorOnly,omitempty"`  // Only alert on major or minor version bumps
+}
+
+// FetchState records the outcome of the last fetch of a source
+type FetchState struct {
+	SourceID     string    `json:"sourceId"`     // ID of the source feed
+	ETag         string    `json:"etag"`         // ETag validator from the last response
+	LastModified string    `json:"lastModified"` // Last-Modified validator from the last response
+	LastStatus   int       `json:"lastStatus"`   // HTTP status of the last fetch (0 on network error)
+	LastFetched  time.Time `json:"lastFetched"`  // When the source was last fetched successfully
+	LastBytes    int64     `json:"lastBytes"`    // Size of the last response body
+	LastError    string    `json:"lastError"`    // Error from the last fetch, if any
+
+	// Local file sources process each file once. ProcessedFiles holds the
+	// content hashes of files already processed, and NewFiles those
+	// processed by this fetch, which are recorded when the state is saved.
+	// StaleFiles are the hashes of processed files no longer present, which
+	// are forgotten when the state is saved.
+	ProcessedFiles map[string]bool `json:"-"`
+	NewFiles       []string        `json:"-"`
+	StaleFiles     []string        `json:"-"`
+
+	// Windowed sources that read only part of their window set Resume to
+	// where the next fetch continues. It is recorded as the fetch time.
+	Resume time.Time `json:"-"`
+
+	// Mailbox sources read replies to threads stored by earlier fetches.
+	// Mentions holds the CVEs those replies name, by the IDs the thread's
+	// item may have, to be recorded once this fetch's items are saved.
+	Mentions map[string][]string `json:"-"`
+}
+
+// WatchedPackage is a dependency whose advisories should raise alerts
+type WatchedPackage struct {
+	Ecosystem string    `json:"ecosystem"`         // Package ecosystem (Go, npm, PyPI, ...)
+	Name      string    `json:"name"`              // Package name within the ecosystem
+	Version   string    `json:"version,omitempty"` // Version in use (empty matches any version)
+	AddedBy   string    `json:"addedBy,omitempty"` // Who added the package
+	Added     time.Time `json:"added,omitempty"`   // When the package was added
+}
+
+// WatchAlert is an advisory that affects watched packages
+type WatchAlert struct {
+	Item     *Intelligence `json:"item"`     // The advisory
+	Packages []string      `json:"packages"` // Matched packages as "ecosystem/name@version"
+}
+
+// SubscriptionFilter selects the items a subscription delivers. Empty
+// fields match every item.
+type SubscriptionFilter struct {
+	Category    Category `json:"category,omitempty"`    // Primary or secondary category
+	SourceID    string   `json:"sourceId,omitempty"`    // ID of the source feed
+	Keywords    []string `json:"keywords,omitempty"`    // Words that must all appear in the title or summary
+	MinSeverity string   `json:"minSeverity,omitempty"` // Lowest severity delivered (LOW, MEDIUM, HIGH or CRITICAL)
+	Vendor      string   `json:"vendor,omitempty"`      // Vendor named in an affected CPE
+}
+
+// Subscription delivers items matching a filter to a user by DM or to a channel
+type Subscription struct {
+	ID        int64              `json:"id"`
+	UserID    string             `json:"userId"`              // User who subscribed, and the DM recipient
+	ChannelID string             `json:"channelId,omitempty"` // Channel to post to instead of a DM
+	GuildID   string             `json:"guildId,omitempty"`   // Guild of the channel
+	Filter    SubscriptionFilter `json:"filter"`
+	Created   time.Time          `json:"created"`
+}
+
+// SubscriptionDelivery is an item queued for delivery to a subscription
+type SubscriptionDelivery struct {
+	Subscription *Subscription `json:"subscription"`
+	Item         *Intelligence `json:"item"`
+}
+
+// SinkDelivery is an item queued for delivery to an outbound sink
+type SinkDelivery struct {
+	SinkID   string        `json:"sinkId"`
+	Item     *Intelligence `json:"item"`
+	Attempts int           `json:"attempts"` // Failed attempts so far
+}
+
+// Digest summarizes the top items of each category over a time window
+type Digest struct {
+	Name     string          `json:"name"`     // Name of the configured digest
+	Since    time.Time       `json:"since"`    // Start of the window
+	Until    time.Time       `json:"until"`    // End of the window
+	Sections []DigestSection `json:"sections"` // Categories with items in the window
+}
+
+// DigestSection lists the top stories of one category in a digest
+type DigestSection struct {
+	Category Category        `json:"category"`
+	Items    []*Intelligence `json:"items"` // Top stories, most severe and recent first
+	Total    int             `json:"total"` // Stories in the category during the window
 }
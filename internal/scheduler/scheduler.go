@@ -1 +1,185 @@
+// internal/scheduler/scheduler.go
+package scheduler
 
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Entry is a snapshot of a scheduled job
+type Entry struct {
+	ID       string        `json:"id"`       // Job identifier (e.g. feed source ID)
+	Interval time.Duration `json:"interval"` // Time between runs
+	NextRun  time.Time     `json:"nextRun"`  // When the job is next due
+	LastRun  time.Time     `json:"lastRun"`  // When the job last completed (zero if never)
+	Running  bool          `json:"running"`  // Whether the job is currently running
+}
+
+// Scheduler tracks when each job is next due. It does not run jobs itself;
+// callers poll Due, run the returned jobs and report back with Done.
+type Scheduler struct {
+	mu      sync.Mutex
+	entries map[string]*Entry
+	jitter  time.Duration
+	rand    *rand.Rand
+	wake    chan struct{}
+}
+
+// New creates a scheduler. Start times and subsequent runs are spread by up
+// to jitter so jobs with equal intervals don't all fire at once.
+func New(jitter time.Duration) *Scheduler {
+	return &Scheduler{
+		entries: make(map[string]*Entry),
+		jitter:  jitter,
+		rand:    rand.New(rand.NewSource(time.Now().UnixNano())),
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// Add registers a job, replacing any existing job with the same ID. The
+// first run is due after a random delay within the jitter window.
+func (s *Scheduler) Add(id string, interval time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[id] = &Entry{
+		ID:       id,
+		Interval: interval,
+		NextRun:  time.Now().Add(s.randomJitter(interval)),
+	}
+	s.notify()
+}
+
+// Remove unregisters a job
+func (s *Scheduler) Remove(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, id)
+	s.notify()
+}
+
+// Due returns the IDs of jobs due at or before now and marks them running
+func (s *Scheduler) Due(now time.Time) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ids []string
+	for id, entry := range s.entries {
+		if entry.Running || entry.NextRun.After(now) {
+			continue
+		}
+		entry.Running = true
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+	return ids
+}
+
+// Done marks a job as finished at the given time and schedules its next run
+func (s *Scheduler) Done(id string, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, exists := s.entries[id]
+	if !exists {
+		return
+	}
+
+	entry.Running = false
+	entry.LastRun = at
+	entry.NextRun = at.Add(entry.Interval).Add(s.randomJitter(entry.Interval))
+	s.notify()
+}
+
+// Trigger makes a job due immediately
+func (s *Scheduler) Trigger(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry, exists := s.entries[id]; exists {
+		entry.NextRun = time.Now()
+		s.notify()
+	}
+}
+
+// TriggerAll makes every job due immediately
+func (s *Scheduler) TriggerAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for _, entry := range s.entries {
+		entry.NextRun = now
+	}
+	s.notify()
+}
+
+// Next returns the earliest next run time of any idle job. The second
+// return value is false if there are no idle jobs.
+func (s *Scheduler) Next() (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var next time.Time
+	found := false
+	for _, entry := range s.entries {
+		if entry.Running {
+			continue
+		}
+		if !found || entry.NextRun.Before(next) {
+			next = entry.NextRun
+			found = true
+		}
+	}
+
+	return next, found
+}
+
+// Wake returns a channel that is signalled whenever the schedule changes
+func (s *Scheduler) Wake() <-chan struct{} {
+	return s.wake
+}
+
+// Entries returns a snapshot of all jobs ordered by next run time
+func (s *Scheduler) Entries() []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := make([]Entry, 0, len(s.entries))
+	for _, entry := range s.entries {
+		entries = append(entries, *entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].NextRun.Equal(entries[j].NextRun) {
+			return entries[i].ID < entries[j].ID
+		}
+		return entries[i].NextRun.Before(entries[j].NextRun)
+	})
+	return entries
+}
+
+// randomJitter returns a random delay up to the jitter window, capped at a
+// tenth of the interval so short intervals are not skewed too far
+func (s *Scheduler) randomJitter(interval time.Duration) time.Duration {
+	max := s.jitter
+	if limit := interval / 10; limit < max {
+		max = limit
+	}
+	if max <= 0 {
+		return 0
+	}
+	return time.Duration(s.rand.Int63n(int64(max)))
+}
+
+// notify signals the wake channel without blocking. Must be called with mu held.
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,66 @@
+// internal/sinks/discord.go
+package sinks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+	"github.com/bwmarrin/discordgo"
+)
+
+// DiscordWebhook posts items to a Discord channel webhook, without a bot
+// session
+type DiscordWebhook struct {
+	url    string
+	client *http.Client
+}
+
+// Send implements Sink
+func (w *DiscordWebhook) Send(ctx context.Context, item *models.Intelligence) error {
+	embed := &discordgo.MessageEmbed{
+		Title:       truncate(item.Title, 256),
+		URL:         item.URL,
+		Description: truncate(item.Summary, 2048),
+		Color:       severityColor(item),
+		Timestamp:   item.Published.UTC().Format(time.RFC3339),
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Category", Value: string(item.Category), Inline: true},
+			{Name: "Source", Value: item.SourceID, Inline: true},
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("ID: %s", item.ID),
+		},
+	}
+	if severity := itemSeverity(item); severity != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Severity", Value: severity, Inline: true})
+	}
+	if item.CVEID != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "CVE", Value: item.CVEID, Inline: true})
+	}
+
+	// WebhookParams always sends a components field, which channel webhooks
+	// not owned by an application cannot use
+	body, err := json.Marshal(map[string]interface{}{
+		"username": "Infopulse",
+		"embeds":   []*discordgo.MessageEmbed{embed},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %v", err)
+	}
+
+	// Wait for the message to be created so errors are reported
+	url := w.url
+	if !strings.Contains(url, "wait=") {
+		if strings.Contains(url, "?") {
+			url += "&wait=true"
+		} else {
+			url += "?wait=true"
+		}
+	}
+	return post(ctx, w.client, url, body, nil)
+}
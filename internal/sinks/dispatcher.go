@@ -0,0 +1,195 @@
+// internal/sinks/dispatcher.go
+package sinks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/config"
+	"github.com/NullMeDev/Infopulse-Node/internal/feeds"
+	"github.com/NullMeDev/Infopulse-Node/internal/logger"
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+)
+
+const (
+	// pollInterval is how often the delivery queue is checked
+	pollInterval = 15 * time.Second
+	// batchSize limits how many deliveries are attempted per pass
+	batchSize = 50
+	// initialBackoff is the delay before the first retry; it doubles with
+	// each further failure up to maxBackoff
+	initialBackoff = 30 * time.Second
+	maxBackoff     = time.Hour
+)
+
+// Dispatcher delivers queued items to the configured sinks, retrying
+// failures with exponential backoff. It needs no Discord session, so it
+// runs alongside the bot or without it.
+type Dispatcher struct {
+	engine  *feeds.Engine
+	logger  *logger.Logger
+	sinks   map[string]Sink
+	configs map[string]config.SinkConfig
+	// paused holds sinks that failed, until their next retry, so one
+	// unreachable endpoint is not sent every queued item in turn
+	paused   map[string]time.Time
+	cancel   context.CancelFunc
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewDispatcher creates a dispatcher for the sinks in the configuration
+func NewDispatcher(cfg *config.Config, engine *feeds.Engine, logger *logger.Logger) (*Dispatcher, error) {
+	client := &http.Client{
+		Timeout: time.Duration(cfg.FetchTimeoutSeconds) * time.Second,
+	}
+
+	d := &Dispatcher{
+		engine:   engine,
+		logger:   logger,
+		sinks:    make(map[string]Sink),
+		configs:  make(map[string]config.SinkConfig),
+		paused:   make(map[string]time.Time),
+		stopChan: make(chan struct{}),
+	}
+
+	for _, sinkConfig := range cfg.Sinks {
+		sink, err := New(sinkConfig, client)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create sink %s: %v", sinkConfig.ID, err)
+		}
+		d.sinks[sinkConfig.ID] = sink
+		d.configs[sinkConfig.ID] = sinkConfig
+	}
+
+	return d, nil
+}
+
+// Start starts delivering in the background
+func (d *Dispatcher) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+
+	d.logger.Info("Sinks", fmt.Sprintf("Delivering to %d sinks", len(d.sinks)))
+
+	d.wg.Add(1)
+	go d.loop(ctx)
+}
+
+// Stop stops delivering, abandoning any request in flight
+func (d *Dispatcher) Stop() {
+	close(d.stopChan)
+	if d.cancel != nil {
+		d.cancel()
+	}
+	d.wg.Wait()
+}
+
+// loop delivers queued items until stopped
+func (d *Dispatcher) loop(ctx context.Context) {
+	defer d.wg.Done()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		d.deliver(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-d.stopChan:
+			d.logger.Info("Sinks", "Sink dispatcher stopped")
+			return
+		}
+	}
+}
+
+// deliver attempts each due delivery once. Paused sinks are left out of
+// the batch so their backlog doesn't hold up the other sinks.
+func (d *Dispatcher) deliver(ctx context.Context) {
+	skip := make(map[string]bool)
+	for sinkID, until := range d.paused {
+		if time.Now().Before(until) {
+			skip[sinkID] = true
+		} else {
+			delete(d.paused, sinkID)
+		}
+	}
+
+	for _, delivery := range d.engine.GetPendingSinkDeliveries(batchSize, skip) {
+		if ctx.Err() != nil {
+			return
+		}
+		// A sink that failed earlier in this batch stays paused
+		if _, ok := d.paused[delivery.SinkID]; ok {
+			continue
+		}
+
+		sink, ok := d.sinks[delivery.SinkID]
+		if !ok {
+			continue
+		}
+
+		err := sink.Send(ctx, delivery.Item)
+		if err == nil {
+			if err := d.engine.MarkSinkDelivered(delivery.SinkID, delivery.Item.ID); err != nil {
+				d.logger.Error("Sinks", err.Error())
+			}
+			continue
+		}
+		if ctx.Err() != nil {
+			// Shutting down; the delivery stays due
+			return
+		}
+
+		d.failed(delivery, err)
+	}
+}
+
+// failed schedules a retry of a failed delivery, or gives it up after the
+// sink's maximum attempts or an error that retrying cannot fix
+func (d *Dispatcher) failed(delivery *models.SinkDelivery, err error) {
+	sinkID, itemID := delivery.SinkID, delivery.Item.ID
+	attempts := delivery.Attempts + 1
+
+	var statusErr *StatusError
+	permanent := errors.As(err, &statusErr) && statusErr.Permanent()
+
+	if permanent || attempts >= d.configs[sinkID].MaxAttempts {
+		d.logger.Error("Sinks", fmt.Sprintf("Giving up delivery of %s to sink %s after %d attempts: %v",
+			itemID, sinkID, attempts, err))
+		if err := d.engine.MarkSinkFailed(sinkID, itemID, time.Time{}, err.Error()); err != nil {
+			d.logger.Error("Sinks", err.Error())
+		}
+		return
+	}
+
+	delay := backoff(attempts)
+	if statusErr != nil && statusErr.RetryAfter > delay {
+		delay = statusErr.RetryAfter
+	}
+	retryAt := time.Now().Add(delay)
+
+	d.logger.Warning("Sinks", fmt.Sprintf("Delivery of %s to sink %s failed, retrying in %v: %v",
+		itemID, sinkID, delay, err))
+	if err := d.engine.MarkSinkFailed(sinkID, itemID, retryAt, err.Error()); err != nil {
+		d.logger.Error("Sinks", err.Error())
+	}
+	d.paused[sinkID] = retryAt
+}
+
+// backoff returns the delay before retrying after a number of failed attempts
+func backoff(attempts int) time.Duration {
+	delay := initialBackoff
+	for i := 1; i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	return delay
+}
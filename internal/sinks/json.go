@@ -0,0 +1,70 @@
+// internal/sinks/json.go
+package sinks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+)
+
+// Headers of signed JSON payloads
+const (
+	TimestampHeader = "X-Infopulse-Timestamp"
+	SignatureHeader = "X-Infopulse-Signature"
+)
+
+// JSONWebhook posts items as JSON to any endpoint. With a secret, each
+// request is signed so the receiver can check it came from this node.
+type JSONWebhook struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// jsonPayload is the body of a JSON webhook request
+type jsonPayload struct {
+	Event string               `json:"event"`
+	Sent  time.Time            `json:"sent"`
+	Item  *models.Intelligence `json:"item"`
+}
+
+// Send implements Sink
+func (w *JSONWebhook) Send(ctx context.Context, item *models.Intelligence) error {
+	now := time.Now().UTC()
+	body, err := json.Marshal(&jsonPayload{
+		Event: "intelligence.created",
+		Sent:  now,
+		Item:  item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode item: %v", err)
+	}
+
+	header := http.Header{}
+	if w.secret != "" {
+		timestamp := strconv.FormatInt(now.Unix(), 10)
+		header.Set(TimestampHeader, timestamp)
+		header.Set(SignatureHeader, Sign(w.secret, timestamp, body))
+	}
+
+	return post(ctx, w.client, w.url, body, header)
+}
+
+// Sign computes the signature header of a payload: "sha256=" and the hex
+// HMAC-SHA256 of the timestamp, a dot and the body. Including the
+// timestamp lets receivers reject replayed requests.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
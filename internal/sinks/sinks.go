@@ -0,0 +1,140 @@
+// internal/sinks/sinks.go
+package sinks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/config"
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+)
+
+// Sink delivers intelligence items to a service outside Discord's bot API
+type Sink interface {
+	// Send delivers one item. Failed deliveries are retried with backoff.
+	Send(ctx context.Context, item *models.Intelligence) error
+}
+
+// New creates the sink for a configured webhook
+func New(cfg config.SinkConfig, client *http.Client) (Sink, error) {
+	switch cfg.Type {
+	case config.SinkDiscord:
+		return &DiscordWebhook{url: cfg.URL, client: client}, nil
+	case config.SinkSlack:
+		return &SlackWebhook{url: cfg.URL, client: client}, nil
+	case config.SinkTeams:
+		return &TeamsWebhook{url: cfg.URL, client: client}, nil
+	case config.SinkJSON:
+		return &JSONWebhook{url: cfg.URL, secret: cfg.Secret, client: client}, nil
+	default:
+		return nil, fmt.Errorf("unknown sink type %q", cfg.Type)
+	}
+}
+
+// StatusError is returned when a webhook answers with a non-success status
+type StatusError struct {
+	StatusCode int
+	Body       string        // Start of the response body
+	RetryAfter time.Duration // Delay requested by the service, if any
+}
+
+// Error implements the error interface
+func (e *StatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("webhook returned status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("webhook returned status %d: %s", e.StatusCode, e.Body)
+}
+
+// Permanent reports whether retrying the request cannot succeed, e.g.
+// because the webhook was deleted or the payload was rejected
+func (e *StatusError) Permanent() bool {
+	return e.StatusCode >= 400 && e.StatusCode < 500 &&
+		e.StatusCode != http.StatusRequestTimeout && e.StatusCode != http.StatusTooManyRequests
+}
+
+// post sends a JSON body to a webhook
+func post(ctx context.Context, client *http.Client, url string, body []byte, header http.Header) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	statusErr := &StatusError{
+		StatusCode: resp.StatusCode,
+		Body:       strings.TrimSpace(string(snippet)),
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		statusErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	return statusErr
+}
+
+// itemSeverity describes an item's severity and exploitation for a message
+func itemSeverity(item *models.Intelligence) string {
+	var parts []string
+	if item.Severity != "" {
+		parts = append(parts, item.Severity)
+	}
+	if item.CVSSScore > 0 {
+		parts = append(parts, fmt.Sprintf("CVSS %.1f", item.CVSSScore))
+	}
+	if item.Exploited {
+		parts = append(parts, "actively exploited")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// severityColor returns an RGB color for a severity level, matching the
+// colors of the bot's embeds
+func severityColor(item *models.Intelligence) int {
+	if item.Exploited {
+		return 0x8b0000
+	}
+	switch item.Severity {
+	case "CRITICAL":
+		return 0x8b0000
+	case "HIGH":
+		return 0xff0000
+	case "MEDIUM":
+		return 0xffa500
+	case "LOW":
+		return 0xffff00
+	default:
+		return 0x00aaff
+	}
+}
+
+// truncate shortens text to at most max runes
+func truncate(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	if max <= 3 {
+		return string(runes[:max])
+	}
+	return string(runes[:max-3]) + "..."
+}
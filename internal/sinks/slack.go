@@ -0,0 +1,66 @@
+// internal/sinks/slack.go
+package sinks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+)
+
+// SlackWebhook posts items to a Slack incoming webhook
+type SlackWebhook struct {
+	url    string
+	client *http.Client
+}
+
+// slackEscaper escapes the characters Slack treats as markup
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// Send implements Sink
+func (w *SlackWebhook) Send(ctx context.Context, item *models.Intelligence) error {
+	title := slackEscaper.Replace(item.Title)
+	if item.URL != "" {
+		title = fmt.Sprintf("<%s|%s>", item.URL, title)
+	}
+
+	footer := fmt.Sprintf("%s | %s | ID: `%s`", item.Category, slackEscaper.Replace(item.SourceID), item.ID)
+	if severity := itemSeverity(item); severity != "" {
+		footer = severity + " | " + footer
+	}
+
+	blocks := []map[string]interface{}{
+		{
+			"type": "section",
+			"text": map[string]string{
+				"type": "mrkdwn",
+				"text": truncate(fmt.Sprintf("*%s*\n%s", title, slackEscaper.Replace(item.Summary)), 3000),
+			},
+		},
+		{
+			"type": "context",
+			"elements": []map[string]string{
+				{"type": "mrkdwn", "text": footer},
+			},
+		},
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		// Shown in notifications, which don't render blocks
+		"text": truncate(item.Title, 300),
+		"attachments": []map[string]interface{}{
+			{
+				"color":  fmt.Sprintf("#%06x", severityColor(item)),
+				"blocks": blocks,
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %v", err)
+	}
+
+	return post(ctx, w.client, w.url, body, nil)
+}
@@ -0,0 +1,64 @@
+// internal/sinks/teams.go
+package sinks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+)
+
+// TeamsWebhook posts items to a Microsoft Teams connector as a message card
+type TeamsWebhook struct {
+	url    string
+	client *http.Client
+}
+
+// Send implements Sink
+func (w *TeamsWebhook) Send(ctx context.Context, item *models.Intelligence) error {
+	facts := []map[string]string{
+		{"name": "Category", "value": string(item.Category)},
+		{"name": "Source", "value": item.SourceID},
+	}
+	if severity := itemSeverity(item); severity != "" {
+		facts = append(facts, map[string]string{"name": "Severity", "value": severity})
+	}
+	if item.CVEID != "" {
+		facts = append(facts, map[string]string{"name": "CVE", "value": item.CVEID})
+	}
+	facts = append(facts, map[string]string{"name": "ID", "value": item.ID})
+
+	card := map[string]interface{}{
+		"@type":      "MessageCard",
+		"@context":   "https://schema.org/extensions",
+		"summary":    truncate(item.Title, 200),
+		"themeColor": fmt.Sprintf("%06X", severityColor(item)),
+		"title":      item.Title,
+		"sections": []map[string]interface{}{
+			{
+				"text":  truncate(item.Summary, 2000),
+				"facts": facts,
+			},
+		},
+	}
+	if item.URL != "" {
+		card["potentialAction"] = []map[string]interface{}{
+			{
+				"@type": "OpenUri",
+				"name":  "Open Original",
+				"targets": []map[string]string{
+					{"os": "default", "uri": item.URL},
+				},
+			},
+		}
+	}
+
+	body, err := json.Marshal(card)
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %v", err)
+	}
+
+	return post(ctx, w.client, w.url, body, nil)
+}
@@ -0,0 +1,222 @@
+// internal/subscriptions/subscriptions.go
+package subscriptions
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/intel"
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+)
+
+// Parse parses a filter expression such as
+//
+//	category:cybersec severity:high vendor:microsoft "remote code execution"
+//
+// Terms are category:, source:, keyword:, severity: and vendor:. Words
+// without a prefix and quoted phrases are keywords.
+func Parse(expr string) (models.SubscriptionFilter, error) {
+	var filter models.SubscriptionFilter
+
+	terms, err := splitTerms(expr)
+	if err != nil {
+		return filter, err
+	}
+
+	for _, term := range terms {
+		key, value, qualified := strings.Cut(term, ":")
+		if !qualified || strings.ContainsAny(key, " \t") {
+			filter.Keywords = append(filter.Keywords, term)
+			continue
+		}
+		if value == "" {
+			return filter, fmt.Errorf("%s: needs a value", key)
+		}
+
+		switch strings.ToLower(key) {
+		case "category", "cat":
+			category, ok := parseCategory(value)
+			if !ok {
+				return filter, fmt.Errorf("unknown category %q", value)
+			}
+			filter.Category = category
+		case "source", "src":
+			filter.SourceID = value
+		case "keyword", "kw":
+			filter.Keywords = append(filter.Keywords, value)
+		case "severity", "sev":
+			severity := strings.ToUpper(strings.TrimLeft(value, ">="))
+			if intel.SeverityRank(severity) == 0 {
+				return filter, fmt.Errorf("unknown severity %q, expected low, medium, high or critical", value)
+			}
+			filter.MinSeverity = severity
+		case "vendor":
+			filter.Vendor = normalizeVendor(value)
+		default:
+			// Words such as "CVE-2024:..." or URLs are keywords
+			filter.Keywords = append(filter.Keywords, term)
+		}
+	}
+
+	if Empty(filter) {
+		return filter, fmt.Errorf("filter matches everything; give at least one term such as category:cybersec or a keyword")
+	}
+	return filter, nil
+}
+
+// splitTerms splits an expression into terms, keeping double-quoted text
+// together and dropping the quotes
+func splitTerms(expr string) ([]string, error) {
+	var terms []string
+	var current strings.Builder
+	inQuote := false
+
+	flush := func() {
+		if current.Len() > 0 {
+			terms = append(terms, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, r := range expr {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+		case unicode.IsSpace(r) && !inQuote:
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if inQuote {
+		return nil, fmt.Errorf("missing closing quote")
+	}
+	flush()
+
+	return terms, nil
+}
+
+// parseCategory matches a category name case-insensitively
+func parseCategory(value string) (models.Category, bool) {
+	for _, category := range models.Categories {
+		if strings.EqualFold(value, string(category)) {
+			return category, true
+		}
+	}
+	return "", false
+}
+
+// Empty reports whether a filter has no conditions
+func Empty(filter models.SubscriptionFilter) bool {
+	return filter.Category == "" && filter.SourceID == "" && len(filter.Keywords) == 0 &&
+		filter.MinSeverity == "" && filter.Vendor == ""
+}
+
+// Format formats a filter as the expression it was parsed from
+func Format(filter models.SubscriptionFilter) string {
+	var terms []string
+	if filter.Category != "" {
+		terms = append(terms, "category:"+strings.ToLower(string(filter.Category)))
+	}
+	if filter.SourceID != "" {
+		terms = append(terms, "source:"+filter.SourceID)
+	}
+	if filter.MinSeverity != "" {
+		terms = append(terms, "severity:"+strings.ToLower(filter.MinSeverity))
+	}
+	if filter.Vendor != "" {
+		terms = append(terms, "vendor:"+filter.Vendor)
+	}
+	for _, keyword := range filter.Keywords {
+		if strings.ContainsAny(keyword, " \t:") {
+			keyword = `"` + keyword + `"`
+		}
+		terms = append(terms, keyword)
+	}
+	return strings.Join(terms, " ")
+}
+
+// Matching returns the subscriptions whose filters match an item
+func Matching(item *models.Intelligence, subscriptions []*models.Subscription) []*models.Subscription {
+	var matches []*models.Subscription
+	for _, subscription := range subscriptions {
+		if Matches(subscription.Filter, item) {
+			matches = append(matches, subscription)
+		}
+	}
+	return matches
+}
+
+// Matches reports whether an item satisfies every condition of a filter
+func Matches(filter models.SubscriptionFilter, item *models.Intelligence) bool {
+	if filter.Category != "" && !HasCategory(item, filter.Category) {
+		return false
+	}
+
+	if filter.SourceID != "" && !strings.EqualFold(filter.SourceID, item.SourceID) {
+		return false
+	}
+
+	if filter.MinSeverity != "" && intel.SeverityRank(item.Severity) < intel.SeverityRank(filter.MinSeverity) {
+		return false
+	}
+
+	if filter.Vendor != "" && !hasVendor(item, filter.Vendor) {
+		return false
+	}
+
+	if len(filter.Keywords) > 0 {
+		text := strings.ToLower(item.Title + "\n" + item.Summary)
+		for _, keyword := range filter.Keywords {
+			if !strings.Contains(text, strings.ToLower(keyword)) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// HasCategory reports whether an item has a primary or secondary category
+func HasCategory(item *models.Intelligence, category models.Category) bool {
+	if item.Category == category {
+		return true
+	}
+	for _, score := range item.Categories {
+		if score.Category == category {
+			return true
+		}
+	}
+	return false
+}
+
+// hasVendor reports whether any affected CPE of an item names a vendor.
+// CPE 2.3 names look like cpe:2.3:a:vendor:product:..., and CPE 2.2 URIs
+// like cpe:/a:vendor:product:...
+func hasVendor(item *models.Intelligence, vendor string) bool {
+	for _, cpe := range item.CPEs {
+		parts := strings.Split(cpe, ":")
+
+		var name string
+		switch {
+		case len(parts) > 3 && parts[1] == "2.3":
+			name = parts[3]
+		case len(parts) > 2 && strings.HasPrefix(parts[1], "/"):
+			name = parts[2]
+		default:
+			continue
+		}
+
+		if normalizeVendor(name) == vendor {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeVendor lower-cases a vendor name and writes spaces as
+// underscores, as CPE names do
+func normalizeVendor(vendor string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(vendor)), " ", "_")
+}
@@ -0,0 +1,207 @@
+// internal/watchlist/watchlist.go
+package watchlist
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/NullMeDev/Infopulse-Node/internal/models"
+	"github.com/NullMeDev/Infopulse-Node/pkg/utils"
+)
+
+// Matches returns the watched packages affected by an advisory item
+func Matches(item *models.Intelligence, watched []models.WatchedPackage) []models.WatchedPackage {
+	var matches []models.WatchedPackage
+	for _, pkg := range watched {
+		for _, affected := range item.Affected {
+			if Affects(affected, pkg) {
+				matches = append(matches, pkg)
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// Affects reports whether an affected package entry covers a watched
+// package. Watched packages without a version match any affected version;
+// ranges that can't be compared (e.g. git commits) are assumed to match.
+func Affects(affected models.AffectedPackage, pkg models.WatchedPackage) bool {
+	if !strings.EqualFold(affected.Ecosystem, pkg.Ecosystem) || affected.Name != pkg.Name {
+		return false
+	}
+
+	if pkg.Version == "" || (len(affected.Ranges) == 0 && len(affected.Versions) == 0) {
+		return true
+	}
+
+	for _, version := range affected.Versions {
+		if utils.CompareVersions(version, pkg.Version) == 0 {
+			return true
+		}
+	}
+
+	for _, r := range affected.Ranges {
+		if inRange(r, pkg.Version) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// inRange reports whether a version falls within a version range
+func inRange(r models.VersionRange, version string) bool {
+	if strings.EqualFold(r.Type, "GIT") {
+		return true
+	}
+
+	if r.Introduced != "" && r.Introduced != "0" && utils.CompareVersions(version, r.Introduced) < 0 {
+		return false
+	}
+	if r.Fixed != "" && utils.CompareVersions(version, r.Fixed) >= 0 {
+		return false
+	}
+	if r.LastAffected != "" && utils.CompareVersions(version, r.LastAffected) > 0 {
+		return false
+	}
+	return true
+}
+
+// Format formats a watched package as "ecosystem/name@version"
+func Format(pkg models.WatchedPackage) string {
+	name := fmt.Sprintf("%s/%s", pkg.Ecosystem, pkg.Name)
+	if pkg.Version != "" {
+		name += "@" + pkg.Version
+	}
+	return name
+}
+
+// ParseManifest extracts packages from a dependency file. Supported files
+// are go.sum and package-lock.json, detected by file name.
+func ParseManifest(filename string, data []byte) ([]models.WatchedPackage, error) {
+	switch strings.ToLower(filepath.Base(filename)) {
+	case "go.sum":
+		return ParseGoSum(data)
+	case "package-lock.json":
+		return ParsePackageLock(data)
+	default:
+		return nil, fmt.Errorf("unsupported dependency file: %s (expected go.sum or package-lock.json)", filename)
+	}
+}
+
+// ParseGoSum extracts Go modules from a go.sum file, keeping the highest
+// version listed for each module
+func ParseGoSum(data []byte) ([]models.WatchedPackage, error) {
+	versions := make(map[string]string)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		module := fields[0]
+		version := strings.TrimSuffix(fields[1], "/go.mod")
+		if current, ok := versions[module]; !ok || utils.CompareVersions(version, current) > 0 {
+			versions[module] = version
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read go.sum: %v", err)
+	}
+
+	var packages []models.WatchedPackage
+	for module, version := range versions {
+		packages = append(packages, models.WatchedPackage{
+			Ecosystem: "Go",
+			Name:      module,
+			Version:   version,
+		})
+	}
+
+	sortPackages(packages)
+	return packages, nil
+}
+
+// packageLock is the subset of package-lock.json we read. Lockfile v2/v3
+// lists packages by install path; v1 nests dependencies.
+type packageLock struct {
+	Packages map[string]struct {
+		Version string `json:"version"`
+		Link    bool   `json:"link"`
+	} `json:"packages"`
+	Dependencies map[string]packageLockDependency `json:"dependencies"`
+}
+
+// packageLockDependency is a v1 lockfile dependency
+type packageLockDependency struct {
+	Version      string                           `json:"version"`
+	Dependencies map[string]packageLockDependency `json:"dependencies"`
+}
+
+// ParsePackageLock extracts npm packages from a package-lock.json file.
+// Every installed version of a package is returned.
+func ParsePackageLock(data []byte) ([]models.WatchedPackage, error) {
+	var lock packageLock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse package-lock.json: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	var packages []models.WatchedPackage
+	add := func(name, version string) {
+		if name == "" || version == "" || seen[name+"@"+version] {
+			return
+		}
+		seen[name+"@"+version] = true
+		packages = append(packages, models.WatchedPackage{
+			Ecosystem: "npm",
+			Name:      name,
+			Version:   version,
+		})
+	}
+
+	// Lockfile v2/v3
+	for path, pkg := range lock.Packages {
+		if path == "" || pkg.Link {
+			continue // Root project or workspace link
+		}
+		i := strings.LastIndex(path, "node_modules/")
+		if i < 0 {
+			continue
+		}
+		add(path[i+len("node_modules/"):], pkg.Version)
+	}
+
+	// Lockfile v1
+	var walk func(deps map[string]packageLockDependency)
+	walk = func(deps map[string]packageLockDependency) {
+		for name, dep := range deps {
+			add(name, dep.Version)
+			walk(dep.Dependencies)
+		}
+	}
+	if len(lock.Packages) == 0 {
+		walk(lock.Dependencies)
+	}
+
+	sortPackages(packages)
+	return packages, nil
+}
+
+// sortPackages orders packages by name and version
+func sortPackages(packages []models.WatchedPackage) {
+	sort.Slice(packages, func(i, j int) bool {
+		if packages[i].Name != packages[j].Name {
+			return packages[i].Name < packages[j].Name
+		}
+		return utils.CompareVersions(packages[i].Version, packages[j].Version) < 0
+	})
+}
@@ -1 +1,123 @@
+// pkg/utils/utils.go
+package utils
 
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// cvePattern matches CVE identifiers
+var cvePattern = regexp.MustCompile(`(?i)\bCVE-\d{4}-\d{4,}\b`)
+
+// ExtractCVEs returns the distinct CVE identifiers mentioned in the given
+// texts, upper-cased and in order of first appearance
+func ExtractCVEs(texts ...string) []string {
+	var cves []string
+	seen := make(map[string]bool)
+
+	for _, text := range texts {
+		for _, match := range cvePattern.FindAllString(text, -1) {
+			cve := strings.ToUpper(match)
+			if seen[cve] {
+				continue
+			}
+			seen[cve] = true
+			cves = append(cves, cve)
+		}
+	}
+
+	return cves
+}
+
+// CompareVersions compares two semantic versions, returning -1, 0 or 1.
+// A leading "v" and build metadata are ignored, missing components count
+// as zero, and a pre-release sorts before its release.
+func CompareVersions(a, b string) int {
+	aCore, aPre := splitVersion(a)
+	bCore, bPre := splitVersion(b)
+
+	// Compare dotted numeric components
+	for i := 0; i < len(aCore) || i < len(bCore); i++ {
+		var x, y string
+		if i < len(aCore) {
+			x = aCore[i]
+		}
+		if i < len(bCore) {
+			y = bCore[i]
+		}
+		if c := compareIdentifier(x, y); c != 0 {
+			return c
+		}
+	}
+
+	// A release sorts after its pre-releases
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	}
+
+	aParts := strings.Split(aPre, ".")
+	bParts := strings.Split(bPre, ".")
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		if c := compareIdentifier(aParts[i], bParts[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInts(len(aParts), len(bParts))
+}
+
+// splitVersion splits a version into its dotted core and pre-release
+func splitVersion(version string) ([]string, string) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.Index(version, "+"); i >= 0 {
+		version = version[:i]
+	}
+
+	pre := ""
+	if i := strings.Index(version, "-"); i >= 0 {
+		version, pre = version[:i], version[i+1:]
+	}
+
+	return strings.Split(version, "."), pre
+}
+
+// compareIdentifier compares version identifiers numerically when both are
+// numbers and lexically otherwise. Empty identifiers count as zero.
+func compareIdentifier(a, b string) int {
+	if a == "" {
+		a = "0"
+	}
+	if b == "" {
+		b = "0"
+	}
+
+	x, errA := strconv.Atoi(a)
+	y, errB := strconv.Atoi(b)
+	switch {
+	case errA == nil && errB == nil:
+		return compareInts(x, y)
+	case errA == nil:
+		return -1 // Numeric identifiers sort before alphanumeric ones
+	case errB == nil:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// compareInts compares two integers, returning -1, 0 or 1
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}